@@ -1,12 +1,14 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
 	"github.com/guitaripod/compiledthoughts/internal/appstore"
 	"github.com/guitaripod/compiledthoughts/internal/github"
 	"github.com/guitaripod/compiledthoughts/internal/build"
+	"github.com/guitaripod/compiledthoughts/internal/og"
 )
 
 func main() {
@@ -21,11 +23,53 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error fetching App Store data: %v\n", err)
 			os.Exit(1)
 		}
+	case "app-site":
+		if err := appstore.GenerateAppSite(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating app-site metadata: %v\n", err)
+			os.Exit(1)
+		}
 	case "fetch-github":
-		if err := github.FetchData(); err != nil {
+		fs := flag.NewFlagSet("fetch-github", flag.ExitOnError)
+		incremental := fs.Bool("incremental", false, "only refresh repos changed since the last run")
+		sortBy := fs.String("sort", github.SortPinned, "order featured projects after the pinned ones by pinned, stars, trending, recent or dependents")
+		record := fs.Bool("record", false, "append today's metrics to src/data/github-history.jsonl")
+		fs.Parse(os.Args[2:])
+
+		opts := github.FetchOptions{Incremental: *incremental, Sort: *sortBy, Record: *record}
+		if err := github.FetchData(opts); err != nil {
 			fmt.Fprintf(os.Stderr, "Error fetching GitHub data: %v\n", err)
 			os.Exit(1)
 		}
+	case "explain-category":
+		if len(os.Args) < 3 {
+			fmt.Fprintln(os.Stderr, "Usage: ct explain-category <repo>")
+			os.Exit(1)
+		}
+		if err := github.ExplainCategory(os.Args[2]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error explaining category: %v\n", err)
+			os.Exit(1)
+		}
+	case "og":
+		if len(os.Args) > 2 && os.Args[2] == "posts" {
+			fs := flag.NewFlagSet("og posts", flag.ExitOnError)
+			force := fs.Bool("force", false, "re-render every post, even if unchanged")
+			fs.Parse(os.Args[3:])
+
+			if err := og.GeneratePosts(*force); err != nil {
+				fmt.Fprintf(os.Stderr, "Error generating post OG images: %v\n", err)
+				os.Exit(1)
+			}
+			break
+		}
+		if err := og.Generate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating OG image: %v\n", err)
+			os.Exit(1)
+		}
+	case "cache":
+		if err := runCache(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Cache error: %v\n", err)
+			os.Exit(1)
+		}
 	case "prebuild":
 		if err := build.PreBuild(); err != nil {
 			fmt.Fprintf(os.Stderr, "Pre-build error: %v\n", err)
@@ -50,8 +94,33 @@ func printUsage() {
 	fmt.Println()
 	fmt.Println("Commands:")
 	fmt.Println("  fetch-appstore  Fetch latest App Store data")
+	fmt.Println("  app-site        Generate Smart App Banner and app-site association files")
 	fmt.Println("  fetch-github    Fetch latest GitHub repository data")
+	fmt.Println("                    --incremental  only refresh repos changed since the last run")
+	fmt.Println("                    --sort         order featured projects by pinned (default), stars, trending, recent or dependents")
+	fmt.Println("                    --record       append today's metrics to the star history")
+	fmt.Println("  explain-category <repo>  Show which rules categorize a repository")
+	fmt.Println("  og              Render public/og-image.png from config/og.json")
+	fmt.Println("  og posts        Render a card per blog post to public/og/<slug>.png")
+	fmt.Println("                    --force        re-render posts whose frontmatter hasn't changed")
+	fmt.Println("  cache stats     Show GitHub API and registry cache statistics")
+	fmt.Println("  cache clear     Remove cached GitHub API and registry responses")
 	fmt.Println("  prebuild        Run pre-build tasks")
 	fmt.Println("  postbuild       Run post-build optimizations")
 	fmt.Println("  help            Show this help message")
-}
\ No newline at end of file
+}
+
+func runCache(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("missing subcommand: expected stats or clear")
+	}
+
+	switch args[0] {
+	case "stats":
+		return github.CacheStats()
+	case "clear":
+		return github.ClearCache()
+	default:
+		return fmt.Errorf("unknown subcommand: %s", args[0])
+	}
+}
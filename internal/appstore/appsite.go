@@ -0,0 +1,248 @@
+package appstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	siteURL           = "https://compiledthoughts.pages.dev"
+	appSiteConfigPath = "config/apple-app-site.json"
+)
+
+var (
+	teamIDPattern   = regexp.MustCompile(`^[A-Z0-9]{10}$`)
+	bundleIDPattern = regexp.MustCompile(`^[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)+$`)
+	trackIDPattern  = regexp.MustCompile(`/id(\d+)`)
+)
+
+// AppSiteConfig holds the identifiers Apple needs to associate apps with the site.
+// Bundle IDs, paths and web credentials are keyed by the app ID used in apps.json.
+// Only apps with WebCredentials set can autofill passwords for the site.
+type AppSiteConfig struct {
+	TeamID         string              `json:"teamID"`
+	BundleIDs      map[string]string   `json:"bundleIDs"`
+	Paths          map[string][]string `json:"paths"`
+	WebCredentials map[string]bool     `json:"webCredentials,omitempty"`
+}
+
+type AppBanner struct {
+	AppID       string `json:"appId"`
+	AppArgument string `json:"appArgument"`
+	Content     string `json:"content"`
+}
+
+type appSiteAssociation struct {
+	AppLinks       appLinks       `json:"applinks"`
+	WebCredentials webCredentials `json:"webcredentials"`
+}
+
+type appLinks struct {
+	Details []appLinkDetail `json:"details"`
+}
+
+type appLinkDetail struct {
+	AppIDs     []string            `json:"appIDs"`
+	Components []map[string]string `json:"components"`
+}
+
+type webCredentials struct {
+	Apps []string `json:"apps"`
+}
+
+// GenerateAppSite writes Smart App Banner metadata and, when a team ID is
+// configured, the apple-app-site-association file. Everything is validated
+// before either file is written.
+func GenerateAppSite() error {
+	fmt.Println("Generating Apple app-site metadata...")
+
+	appsData, err := loadAppsData(filepath.Join("src", "data", "apps.json"))
+	if err != nil {
+		return err
+	}
+
+	config, err := loadAppSiteConfig(appSiteConfigPath)
+	if err != nil {
+		return err
+	}
+
+	var association *appSiteAssociation
+	if config.TeamID == "" {
+		fmt.Printf("  ⚠️  No team ID in %s or APPLE_TEAM_ID, skipping app-site association\n", appSiteConfigPath)
+	} else {
+		built, err := buildAppSiteAssociation(config, appsData.Apps)
+		if err != nil {
+			return err
+		}
+		association = &built
+	}
+
+	// Smart App Banners only show in Safari on iPhone and iPad
+	banners := make(map[string]AppBanner)
+	for _, app := range appsData.Apps {
+		if !contains(app.Platforms, "iPhone") && !contains(app.Platforms, "iPad") {
+			fmt.Printf("  - %s: no iOS platform, skipping banner\n", app.Name)
+			continue
+		}
+
+		match := trackIDPattern.FindStringSubmatch(app.AppStoreURL)
+		if match == nil {
+			fmt.Printf("  ⚠️  %s: no App Store ID in %s\n", app.Name, app.AppStoreURL)
+			continue
+		}
+
+		appArgument := siteURL + "/apps"
+		banners[app.ID] = AppBanner{
+			AppID:       match[1],
+			AppArgument: appArgument,
+			Content:     fmt.Sprintf("app-id=%s, app-argument=%s", match[1], appArgument),
+		}
+		fmt.Printf("  ✓ %s: app-id=%s\n", app.Name, match[1])
+	}
+
+	bannersPath := filepath.Join("src", "data", "app-banners.json")
+	if err := writeJSON(bannersPath, banners); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Banner metadata written to %s\n", bannersPath)
+
+	if association != nil {
+		associationPath := filepath.Join("public", ".well-known", "apple-app-site-association")
+		if err := writeJSON(associationPath, association); err != nil {
+			return err
+		}
+		fmt.Printf("✓ App site association written to %s\n", associationPath)
+	}
+
+	return nil
+}
+
+func loadAppSiteConfig(path string) (AppSiteConfig, error) {
+	var config AppSiteConfig
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	// Allow CI to supply the team ID without committing it
+	if teamID := os.Getenv("APPLE_TEAM_ID"); teamID != "" {
+		config.TeamID = teamID
+	}
+
+	// An empty team ID skips the association rather than failing
+	if config.TeamID != "" && !teamIDPattern.MatchString(config.TeamID) {
+		return config, fmt.Errorf("invalid team ID %q in %s: expected 10 uppercase letters or digits (set teamID or APPLE_TEAM_ID)", config.TeamID, path)
+	}
+
+	return config, nil
+}
+
+func loadAppsData(path string) (AppsData, error) {
+	var appsData AppsData
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return appsData, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &appsData); err != nil {
+		return appsData, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return appsData, nil
+}
+
+func buildAppSiteAssociation(config AppSiteConfig, apps []App) (appSiteAssociation, error) {
+	association := appSiteAssociation{
+		AppLinks:       appLinks{Details: []appLinkDetail{}},
+		WebCredentials: webCredentials{Apps: []string{}},
+	}
+
+	appIDs := make([]string, 0, len(config.BundleIDs))
+	for id := range config.BundleIDs {
+		appIDs = append(appIDs, id)
+	}
+	sort.Strings(appIDs)
+
+	for _, id := range appIDs {
+		bundleID := config.BundleIDs[id]
+		if err := validateBundleID(bundleID); err != nil {
+			return association, fmt.Errorf("app %s: %w", id, err)
+		}
+		if indexOfApp(apps, id) == -1 {
+			fmt.Printf("  ⚠️  %s: bundle ID configured but app not in apps.json\n", id)
+		}
+
+		// Default to the apps page
+		paths := config.Paths[id]
+		if len(paths) == 0 {
+			paths = []string{"/apps"}
+		}
+
+		components := make([]map[string]string, 0, len(paths))
+		for _, path := range paths {
+			components = append(components, map[string]string{"/": path})
+		}
+
+		fullID := config.TeamID + "." + bundleID
+		association.AppLinks.Details = append(association.AppLinks.Details, appLinkDetail{
+			AppIDs:     []string{fullID},
+			Components: components,
+		})
+		if config.WebCredentials[id] {
+			association.WebCredentials.Apps = append(association.WebCredentials.Apps, fullID)
+		}
+	}
+
+	return association, nil
+}
+
+func validateBundleID(bundleID string) error {
+	if bundleID == "" {
+		return fmt.Errorf("bundle ID is empty")
+	}
+	if len(bundleID) > 155 {
+		return fmt.Errorf("bundle ID %q is longer than 155 characters", bundleID)
+	}
+	if !bundleIDPattern.MatchString(bundleID) {
+		return fmt.Errorf("invalid bundle ID %q: expected reverse-DNS form using letters, digits, hyphens and periods", bundleID)
+	}
+	if strings.HasPrefix(bundleID, "com.apple.") {
+		return fmt.Errorf("invalid bundle ID %q: the com.apple prefix is reserved", bundleID)
+	}
+	return nil
+}
+
+func indexOfApp(apps []App, id string) int {
+	for i, app := range apps {
+		if app.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func writeJSON(path string, v interface{}) error {
+	// Ensure directory exists
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(jsonData, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package appstore
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateBundleID(t *testing.T) {
+	tests := map[string]bool{
+		"com.guitaripod.Pixie":            true,
+		"dev.guitaripod.apod-cli":         true,
+		"io.example.App2.widget":          true,
+		"":                                false,
+		"Pixie":                           false,
+		"com.guitaripod.":                 false,
+		".com.guitaripod":                 false,
+		"com.guitaripod.my_app":           false,
+		"com.guitaripod.my app":           false,
+		"com.apple.Pixie":                 false,
+		"com." + strings.Repeat("a", 152): false,
+	}
+	for bundleID, valid := range tests {
+		if err := validateBundleID(bundleID); (err == nil) != valid {
+			t.Errorf("validateBundleID(%q) = %v, want valid %v", bundleID, err, valid)
+		}
+	}
+}
+
+func TestBuildAppSiteAssociation(t *testing.T) {
+	config := AppSiteConfig{
+		TeamID: "ABCDE12345",
+		BundleIDs: map[string]string{
+			"pixie":  "com.guitaripod.Pixie",
+			"aurora": "com.guitaripod.Aurora",
+		},
+		Paths:          map[string][]string{"pixie": {"/apps", "/blog/pixie-*"}},
+		WebCredentials: map[string]bool{"pixie": true},
+	}
+	apps := []App{{ID: "pixie"}, {ID: "aurora"}}
+
+	association, err := buildAppSiteAssociation(config, apps)
+	if err != nil {
+		t.Fatalf("buildAppSiteAssociation: %v", err)
+	}
+	got, err := json.Marshal(association)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"applinks":{"details":[` +
+		`{"appIDs":["ABCDE12345.com.guitaripod.Aurora"],"components":[{"/":"/apps"}]},` +
+		`{"appIDs":["ABCDE12345.com.guitaripod.Pixie"],"components":[{"/":"/apps"},{"/":"/blog/pixie-*"}]}]},` +
+		`"webcredentials":{"apps":["ABCDE12345.com.guitaripod.Pixie"]}}`
+	if string(got) != want {
+		t.Errorf("association =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestBuildAppSiteAssociationRejectsInvalidBundleID(t *testing.T) {
+	config := AppSiteConfig{TeamID: "ABCDE12345", BundleIDs: map[string]string{"pixie": "com.apple.Pixie"}}
+	if _, err := buildAppSiteAssociation(config, nil); err == nil || !strings.Contains(err.Error(), "pixie") {
+		t.Errorf("buildAppSiteAssociation = %v, want an error naming the app", err)
+	}
+}
+
+func TestLoadAppSiteConfig(t *testing.T) {
+	t.Setenv("APPLE_TEAM_ID", "")
+
+	tests := []struct {
+		name    string
+		config  string
+		env     string
+		teamID  string
+		wantErr bool
+	}{
+		{name: "no team ID", config: `{"teamID": ""}`},
+		{name: "team ID", config: `{"teamID": "ABCDE12345"}`, teamID: "ABCDE12345"},
+		{name: "environment wins", config: `{"teamID": "ABCDE12345"}`, env: "ZYXWV98765", teamID: "ZYXWV98765"},
+		{name: "invalid team ID", config: `{"teamID": "abc"}`, wantErr: true},
+		{name: "invalid JSON", config: `{`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("APPLE_TEAM_ID", tt.env)
+			path := filepath.Join(t.TempDir(), "apple-app-site.json")
+			if err := os.WriteFile(path, []byte(tt.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := loadAppSiteConfig(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadAppSiteConfig error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && config.TeamID != tt.teamID {
+				t.Errorf("TeamID = %q, want %q", config.TeamID, tt.teamID)
+			}
+		})
+	}
+}
+
+func TestGenerateAppSiteValidatesBeforeWriting(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("APPLE_TEAM_ID", "")
+
+	files := map[string]string{
+		"src/data/apps.json": `{"apps": [{"id": "pixie", "name": "Pixie", "platforms": ["iPhone"],
+			"appStoreUrl": "https://apps.apple.com/app/pixie/id123456789"}]}`,
+		appSiteConfigPath: `{"teamID": "ABCDE12345", "bundleIDs": {"pixie": "not a bundle ID"}}`,
+	}
+	for path, contents := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := GenerateAppSite(); err == nil {
+		t.Fatal("GenerateAppSite returned no error for an invalid bundle ID")
+	}
+	if _, err := os.Stat("src/data/app-banners.json"); !os.IsNotExist(err) {
+		t.Error("banner metadata was written despite the invalid config")
+	}
+
+	// Without a team ID only the banners are written
+	if err := os.WriteFile(appSiteConfigPath, []byte(`{"teamID": ""}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateAppSite(); err != nil {
+		t.Fatalf("GenerateAppSite: %v", err)
+	}
+	banners, err := os.ReadFile("src/data/app-banners.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(banners), `"appId": "123456789"`) {
+		t.Errorf("banners = %s, want Pixie's App Store ID", banners)
+	}
+	if _, err := os.Stat("public/.well-known/apple-app-site-association"); !os.IsNotExist(err) {
+		t.Error("association was written without a team ID")
+	}
+}
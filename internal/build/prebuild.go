@@ -2,10 +2,10 @@ package build
 
 import (
 	"fmt"
-	"os/exec"
 
 	"github.com/guitaripod/compiledthoughts/internal/appstore"
 	"github.com/guitaripod/compiledthoughts/internal/github"
+	"github.com/guitaripod/compiledthoughts/internal/og"
 )
 
 func PreBuild() error {
@@ -17,23 +17,28 @@ func PreBuild() error {
 		// Don't fail the build if App Store fetch fails
 	}
 
+	// Regenerate Smart App Banner metadata and the app-site association
+	if err := appstore.GenerateAppSite(); err != nil {
+		fmt.Printf("Failed to generate app-site metadata: %v\n", err.Error())
+		// The previously generated files stay in place
+	}
+
 	// Fetch latest GitHub data
-	if err := github.FetchData(); err != nil {
+	if err := github.FetchData(github.FetchOptions{}); err != nil {
 		fmt.Printf("Failed to fetch GitHub data: %v\n", err.Error())
 		// Don't fail the build if GitHub fetch fails
 	}
 
-	// Generate OG image (still using Node.js script for now)
-	fmt.Println("Generating OG image...")
-	cmd := exec.Command("node", "scripts/generate-main-og-image.js")
-	cmd.Stdout = nil // Hide output since it's handled by the script
-	cmd.Stderr = nil
-	if err := cmd.Run(); err != nil {
+	// Generate OG image
+	if err := og.Generate(); err != nil {
 		fmt.Printf("Failed to generate OG image: %v\n", err.Error())
 		// Don't fail the build if OG image generation fails
-	} else {
-		fmt.Println("✓ Generated og-image.png")
-		fmt.Println("✓ OG image generated successfully")
+	}
+
+	// Generate per-post OG images
+	if err := og.GeneratePosts(false); err != nil {
+		fmt.Printf("Failed to generate post OG images: %v\n", err.Error())
+		// Posts without a card fall back to the main OG image
 	}
 
 	fmt.Println("✓ Pre-build tasks complete")
@@ -0,0 +1,249 @@
+package github
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var cacheDir = filepath.Join(".cache", "ct", "github")
+
+// Headers worth replaying from a cached response
+var cachedHeaders = []string{"Content-Type", "Link"}
+
+// cacheEntry is a stored response body with the validators GitHub needs to
+// answer a conditional request with 304 Not Modified.
+type cacheEntry struct {
+	URL          string            `json:"url"`
+	ETag         string            `json:"etag,omitempty"`
+	LastModified string            `json:"lastModified,omitempty"`
+	Header       map[string]string `json:"header"`
+	Body         []byte            `json:"body"`
+	StoredAt     time.Time         `json:"storedAt"`
+}
+
+// diskCache persists GET responses so unchanged resources can be revalidated
+// with If-None-Match. 304 responses don't count against the GitHub quota.
+type diskCache struct {
+	dir    string
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+func newDiskCache(dir string) *diskCache {
+	return &diskCache{dir: dir}
+}
+
+func (c *diskCache) path(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Method + " " + req.URL.String() + " " + req.Header.Get("Accept")))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *diskCache) load(req *http.Request) (*cacheEntry, bool) {
+	data, err := os.ReadFile(c.path(req))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// prepare adds conditional headers to the request when a cached copy exists.
+func (c *diskCache) prepare(req *http.Request) *cacheEntry {
+	if req.Method != http.MethodGet {
+		return nil
+	}
+
+	entry, ok := c.load(req)
+	if !ok {
+		return nil
+	}
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+	return entry
+}
+
+// resolve turns a 304 into the cached response and stores fresh 200s.
+func (c *diskCache) resolve(req *http.Request, resp *http.Response, entry *cacheEntry) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return resp, nil
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		c.hits.Add(1)
+
+		cached := &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Proto:      resp.Proto,
+			ProtoMajor: resp.ProtoMajor,
+			ProtoMinor: resp.ProtoMinor,
+			Header:     resp.Header.Clone(),
+			Body:       io.NopCloser(bytes.NewReader(entry.Body)),
+			Request:    req,
+		}
+		for name, value := range entry.Header {
+			cached.Header.Set(name, value)
+		}
+		return cached, nil
+	}
+
+	c.misses.Add(1)
+
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	stored := cacheEntry{
+		URL:          req.URL.String(),
+		ETag:         etag,
+		LastModified: lastModified,
+		Header:       make(map[string]string),
+		Body:         body,
+		StoredAt:     time.Now(),
+	}
+	for _, name := range cachedHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			stored.Header[name] = value
+		}
+	}
+
+	// A failed write only costs quota on the next run
+	if err := c.store(req, stored); err != nil {
+		fmt.Printf("    ⚠️  Failed to cache %s: %v\n", req.URL.Path, err)
+	}
+	return resp, nil
+}
+
+func (c *diskCache) store(req *http.Request, entry cacheEntry) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Write atomically so concurrent workers never read a partial entry
+	tmp, err := os.CreateTemp(c.dir, "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(req))
+}
+
+// cachedSources are the directories responses are cached in, by the
+// client that fills them
+var cachedSources = []struct {
+	name string
+	dir  *string
+}{
+	{"GitHub API", &cacheDir},
+	{"Package registries", &registryCacheDir},
+}
+
+// CacheStats prints the number, size and age of cached responses, separately
+// for the GitHub API and the package registries.
+func CacheStats() error {
+	for i, source := range cachedSources {
+		if i > 0 {
+			fmt.Println()
+		}
+		if err := printCacheStats(source.name, *source.dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printCacheStats(name, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		fmt.Printf("%s cache %s is empty\n", name, dir)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var (
+		count          int
+		size           int64
+		oldest, newest time.Time
+	)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+
+		count++
+		size += info.Size()
+		if oldest.IsZero() || info.ModTime().Before(oldest) {
+			oldest = info.ModTime()
+		}
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+	}
+
+	fmt.Printf("%s cache: %s\n", name, dir)
+	fmt.Printf("  Entries: %d\n", count)
+	fmt.Printf("  Size: %.1f KB\n", float64(size)/1024)
+	if count > 0 {
+		fmt.Printf("  Oldest entry: %s\n", oldest.Format(time.RFC3339))
+		fmt.Printf("  Newest entry: %s\n", newest.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// ClearCache removes every cached GitHub API and registry response.
+func ClearCache() error {
+	for _, source := range cachedSources {
+		if err := os.RemoveAll(*source.dir); err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		fmt.Printf("✓ Cleared %s\n", *source.dir)
+	}
+	return nil
+}
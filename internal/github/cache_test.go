@@ -0,0 +1,90 @@
+package github
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiskCacheRevalidates(t *testing.T) {
+	var conditional []string
+	srv := fakeHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditional = append(conditional, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Link", `<`+r.URL.String()+`&page=2>; rel="next"`)
+		io.WriteString(w, `[{"name":"ct"}]`)
+	}))
+
+	c := &client{http: srv.Client(), cache: newDiskCache(t.TempDir()), remaining: -1}
+	get := func() (int, string, string) {
+		t.Helper()
+		req, err := http.NewRequest("GET", srv.URL+"/users/guitaripod/repos?per_page=100", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := c.do(req)
+		if err != nil {
+			t.Fatalf("do: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(body), resp.Header.Get("Link")
+	}
+
+	status, first, link := get()
+	if status != http.StatusOK || first != `[{"name":"ct"}]` || link == "" {
+		t.Fatalf("first response = %d %q, Link %q", status, first, link)
+	}
+
+	status, second, cachedLink := get()
+	if status != http.StatusOK || second != first {
+		t.Errorf("revalidated response = %d %q, want 200 with the cached body", status, second)
+	}
+	if cachedLink != link {
+		t.Errorf("revalidated Link = %q, want the cached %q", cachedLink, link)
+	}
+	if len(conditional) != 2 || conditional[0] != "" || conditional[1] != `"v1"` {
+		t.Errorf("If-None-Match sent = %q, want none, then the stored ETag", conditional)
+	}
+	if hits, misses := c.cache.hits.Load(), c.cache.misses.Load(); hits != 1 || misses != 1 {
+		t.Errorf("hits, misses = %d, %d, want 1, 1", hits, misses)
+	}
+}
+
+func TestClearCacheRemovesEverySource(t *testing.T) {
+	api, registry := cacheDir, registryCacheDir
+	t.Cleanup(func() { cacheDir, registryCacheDir = api, registry })
+
+	root := t.TempDir()
+	cacheDir = filepath.Join(root, "github")
+	registryCacheDir = filepath.Join(root, "registries")
+	for _, dir := range []string{cacheDir, registryCacheDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "entry.json"), []byte(`{}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := CacheStats(); err != nil {
+		t.Fatalf("CacheStats: %v", err)
+	}
+	if err := ClearCache(); err != nil {
+		t.Fatalf("ClearCache: %v", err)
+	}
+	for _, dir := range []string{cacheDir, registryCacheDir} {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("%s still exists", dir)
+		}
+	}
+}
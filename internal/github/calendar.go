@@ -0,0 +1,197 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const calendarQuery = `query($login: String!) {
+	user(login: $login) {
+		contributionsCollection {
+			contributionCalendar {
+				totalContributions
+				weeks {
+					contributionDays {
+						date
+						contributionCount
+						contributionLevel
+					}
+				}
+			}
+		}
+	}
+}`
+
+var calendarPath = filepath.Join("src", "data", "contributions.json")
+
+// GitHub's quartile buckets, as used by the profile heatmap
+var contributionLevels = map[string]int{
+	"NONE":            0,
+	"FIRST_QUARTILE":  1,
+	"SECOND_QUARTILE": 2,
+	"THIRD_QUARTILE":  3,
+	"FOURTH_QUARTILE": 4,
+}
+
+// ContributionCalendar is the past year of daily contribution counts with
+// the statistics a static heatmap shows next to it.
+type ContributionCalendar struct {
+	LastUpdated        string             `json:"lastUpdated"`
+	TotalContributions int                `json:"totalContributions"`
+	CurrentStreak      int                `json:"currentStreak"`
+	LongestStreak      Streak             `json:"longestStreak"`
+	BusiestWeekday     string             `json:"busiestWeekday"`
+	BusiestDay         *ContributionDay   `json:"busiestDay,omitempty"`
+	Weekdays           map[string]int     `json:"weekdays"` // total contributions per weekday
+	Weeks              []ContributionWeek `json:"weeks"`
+}
+
+// ContributionWeek holds up to seven days starting on Sunday. The first and
+// last weeks of the year can be partial.
+type ContributionWeek struct {
+	Days []ContributionDay `json:"days"`
+}
+
+type ContributionDay struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+	Level int    `json:"level"` // 0-4
+}
+
+type Streak struct {
+	Days  int    `json:"days"`
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+type calendarResponse struct {
+	User struct {
+		ContributionsCollection struct {
+			ContributionCalendar struct {
+				TotalContributions int `json:"totalContributions"`
+				Weeks              []struct {
+					ContributionDays []struct {
+						Date              string `json:"date"`
+						ContributionCount int    `json:"contributionCount"`
+						ContributionLevel string `json:"contributionLevel"`
+					} `json:"contributionDays"`
+				} `json:"weeks"`
+			} `json:"contributionCalendar"`
+		} `json:"contributionsCollection"`
+	} `json:"user"`
+}
+
+// fetchContributionCalendar reads the user's past year of contributions.
+// GraphQL requires credentials.
+func fetchContributionCalendar() (*ContributionCalendar, error) {
+	var data calendarResponse
+	if err := graphQL(calendarQuery, map[string]interface{}{"login": githubUsername}, &data); err != nil {
+		return nil, err
+	}
+
+	source := data.User.ContributionsCollection.ContributionCalendar
+	calendar := &ContributionCalendar{
+		LastUpdated:        time.Now().Format(time.RFC3339),
+		TotalContributions: source.TotalContributions,
+		Weeks:              make([]ContributionWeek, 0, len(source.Weeks)),
+	}
+	for _, week := range source.Weeks {
+		days := make([]ContributionDay, 0, len(week.ContributionDays))
+		for _, day := range week.ContributionDays {
+			days = append(days, ContributionDay{
+				Date:  day.Date,
+				Count: day.ContributionCount,
+				Level: contributionLevels[day.ContributionLevel],
+			})
+		}
+		calendar.Weeks = append(calendar.Weeks, ContributionWeek{Days: days})
+	}
+
+	calendar.summarize()
+	return calendar, nil
+}
+
+// summarize derives the streaks and busiest days from the weeks.
+func (c *ContributionCalendar) summarize() {
+	var days []ContributionDay
+	for _, week := range c.Weeks {
+		days = append(days, week.Days...)
+	}
+
+	c.Weekdays = make(map[string]int, 7)
+	c.BusiestDay = nil
+	c.LongestStreak = Streak{}
+
+	var run Streak
+	for i, day := range days {
+		if date, err := time.Parse("2006-01-02", day.Date); err == nil {
+			c.Weekdays[date.Weekday().String()] += day.Count
+		}
+		if day.Count > 0 && (c.BusiestDay == nil || day.Count > c.BusiestDay.Count) {
+			c.BusiestDay = &days[i]
+		}
+
+		if day.Count == 0 {
+			run = Streak{}
+			continue
+		}
+		if run.Days == 0 {
+			run.Start = day.Date
+		}
+		run.Days++
+		run.End = day.Date
+		if run.Days > c.LongestStreak.Days {
+			c.LongestStreak = run
+		}
+	}
+
+	// Today isn't over, so a streak is still current if it ended yesterday
+	c.CurrentStreak = 0
+	end := len(days) - 1
+	if end >= 0 && days[end].Count == 0 {
+		end--
+	}
+	for i := end; i >= 0 && days[i].Count > 0; i-- {
+		c.CurrentStreak++
+	}
+
+	c.BusiestWeekday = ""
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		name := weekday.String()
+		if c.Weekdays[name] > 0 && (c.BusiestWeekday == "" || c.Weekdays[name] > c.Weekdays[c.BusiestWeekday]) {
+			c.BusiestWeekday = name
+		}
+	}
+}
+
+// writeContributionCalendar fetches the calendar and writes it for the
+// static heatmap.
+func writeContributionCalendar() error {
+	calendar, err := fetchContributionCalendar()
+	if err != nil {
+		return err
+	}
+	// An empty answer would replace the last good calendar with nothing
+	if len(calendar.Weeks) == 0 {
+		return fmt.Errorf("no contribution weeks returned, keeping %s", calendarPath)
+	}
+
+	jsonData, err := json.MarshalIndent(calendar, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal contribution calendar: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(calendarPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(calendarPath, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", calendarPath, err)
+	}
+
+	fmt.Printf("✓ %d contributions in the past year, current streak %d days, longest %d days\n",
+		calendar.TotalContributions, calendar.CurrentStreak, calendar.LongestStreak.Days)
+	fmt.Printf("✓ Contribution calendar written to %s\n", calendarPath)
+	return nil
+}
@@ -0,0 +1,104 @@
+package github
+
+import (
+	"testing"
+	"time"
+)
+
+// calendarOf spreads daily counts over Sunday-first weeks starting on
+// 2025-01-05, a Sunday.
+func calendarOf(counts ...int) *ContributionCalendar {
+	start := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+	c := &ContributionCalendar{}
+	for i, count := range counts {
+		if i%7 == 0 {
+			c.Weeks = append(c.Weeks, ContributionWeek{})
+		}
+		week := &c.Weeks[len(c.Weeks)-1]
+		week.Days = append(week.Days, ContributionDay{Date: start.AddDate(0, 0, i).Format("2006-01-02"), Count: count})
+	}
+	return c
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name     string
+		counts   []int
+		current  int
+		longest  Streak
+		busiest  string // date of the busiest day
+		weekday  string
+		weekdays map[string]int
+	}{
+		{
+			name:    "streak running through today",
+			counts:  []int{1, 0, 2, 3, 1},
+			current: 3,
+			longest: Streak{Days: 3, Start: "2025-01-07", End: "2025-01-09"},
+			busiest: "2025-01-08",
+			weekday: "Wednesday",
+		},
+		{
+			name:    "streak ending yesterday is current",
+			counts:  []int{4, 4, 0, 1, 1, 0},
+			current: 2,
+			longest: Streak{Days: 2, Start: "2025-01-05", End: "2025-01-06"},
+			busiest: "2025-01-05",
+			weekday: "Sunday",
+		},
+		{
+			name:    "streak ending before yesterday is over",
+			counts:  []int{1, 1, 1, 0, 0},
+			longest: Streak{Days: 3, Start: "2025-01-05", End: "2025-01-07"},
+			busiest: "2025-01-05",
+			weekday: "Sunday",
+		},
+		{
+			name:    "longest streak across weeks",
+			counts:  []int{0, 0, 0, 0, 0, 2, 2, 2, 2, 0, 5},
+			current: 1,
+			longest: Streak{Days: 4, Start: "2025-01-10", End: "2025-01-13"},
+			busiest: "2025-01-15",
+			weekday: "Wednesday",
+			weekdays: map[string]int{
+				"Sunday": 2, "Monday": 2, "Tuesday": 0, "Wednesday": 5,
+				"Thursday": 0, "Friday": 2, "Saturday": 2,
+			},
+		},
+		{
+			name:   "no contributions",
+			counts: []int{0, 0, 0},
+		},
+		{
+			name: "no days",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := calendarOf(tt.counts...)
+			c.summarize()
+
+			if c.CurrentStreak != tt.current {
+				t.Errorf("CurrentStreak = %d, want %d", c.CurrentStreak, tt.current)
+			}
+			if c.LongestStreak != tt.longest {
+				t.Errorf("LongestStreak = %+v, want %+v", c.LongestStreak, tt.longest)
+			}
+			switch {
+			case tt.busiest == "" && c.BusiestDay != nil:
+				t.Errorf("BusiestDay = %+v, want none", c.BusiestDay)
+			case tt.busiest != "" && (c.BusiestDay == nil || c.BusiestDay.Date != tt.busiest):
+				t.Errorf("BusiestDay = %+v, want %s", c.BusiestDay, tt.busiest)
+			}
+			if c.BusiestWeekday != tt.weekday {
+				t.Errorf("BusiestWeekday = %q, want %q", c.BusiestWeekday, tt.weekday)
+			}
+			for weekday, want := range tt.weekdays {
+				if got := c.Weekdays[weekday]; got != want {
+					t.Errorf("Weekdays[%s] = %d, want %d", weekday, got, want)
+				}
+			}
+		})
+	}
+}
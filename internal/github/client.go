@@ -0,0 +1,274 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Requests kept in reserve so concurrent workers don't overshoot the quota
+	rateLimitReserve = 5
+	// Give up instead of stalling the build when the quota resets too far out
+	maxRateLimitWait = 5 * time.Minute
+	maxAttempts      = 3
+)
+
+// client paces GitHub API requests using the rate-limit headers of previous
+// responses. It is safe for concurrent use by the fetch workers.
+type client struct {
+	http  *http.Client
+	cache *diskCache
+	auth  *credentials
+
+	mu          sync.Mutex
+	remaining   int
+	limit       int
+	reset       time.Time
+	pausedUntil time.Time
+}
+
+var apiClient = newClient(cacheDir)
+
+// newClient returns a client that caches GET responses in dir.
+func newClient(dir string) *client {
+	return &client{
+		http:      &http.Client{Timeout: 30 * time.Second},
+		cache:     newDiskCache(dir),
+		remaining: -1,
+	}
+}
+
+// do sends the request, waiting for the rate-limit window when the quota is
+// nearly exhausted and retrying responses that ask the client to back off.
+// GET requests are revalidated against the on-disk cache. Requests to the
+// GitHub API are authenticated unless they already carry credentials.
+func (c *client) do(req *http.Request) (*http.Response, error) {
+	if c.auth != nil && isAPIRequest(req) && req.Header.Get("Authorization") == "" {
+		if err := c.auth.authorize(req); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := c.wait(); err != nil {
+			return nil, err
+		}
+
+		attemptReq := req
+		if attempt > 1 {
+			var err error
+			if attemptReq, err = cloneRequest(req); err != nil {
+				return nil, err
+			}
+		}
+
+		var entry *cacheEntry
+		if c.cache != nil {
+			entry = c.cache.prepare(attemptReq)
+		}
+
+		resp, err := c.http.Do(attemptReq)
+		if err != nil {
+			return nil, err
+		}
+		c.update(resp)
+
+		if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+			return c.finish(attemptReq, resp, entry)
+		}
+
+		delay, limited := c.backoff(resp)
+		if !limited || attempt >= maxAttempts {
+			return c.finish(attemptReq, resp, entry)
+		}
+		resp.Body.Close()
+
+		fmt.Printf("    ⚠️  Rate limited on %s, waiting %v before retry...\n", req.URL.Path, delay.Round(time.Second))
+		c.pause(delay)
+	}
+}
+
+// isAPIRequest reports whether the request goes to the configured GitHub
+// REST or GraphQL endpoint, so credentials never leak to other hosts.
+func isAPIRequest(req *http.Request) bool {
+	if req.URL.Host == apiHost {
+		return true
+	}
+	graphql, err := url.Parse(graphQLURL)
+	return err == nil && req.URL.Host == graphql.Host
+}
+
+func (c *client) finish(req *http.Request, resp *http.Response, entry *cacheEntry) (*http.Response, error) {
+	if c.cache == nil {
+		return resp, nil
+	}
+	return c.cache.resolve(req, resp, entry)
+}
+
+// wait blocks until the client is allowed to send another request, then
+// reserves it by counting it against the remaining quota. Checking and
+// reserving under one lock keeps concurrent workers from passing the reserve
+// together.
+func (c *client) wait() error {
+	for {
+		c.mu.Lock()
+		until := c.pausedUntil
+		if c.remaining >= 0 && c.remaining <= rateLimitReserve && c.reset.After(until) {
+			until = c.reset
+		}
+		delay := time.Until(until)
+		if delay <= 0 {
+			if c.remaining > 0 {
+				c.remaining--
+			} else if c.remaining == 0 {
+				// The window has reset; the next response reports the new quota
+				c.remaining = -1
+			}
+			c.mu.Unlock()
+			return nil
+		}
+		c.mu.Unlock()
+
+		if delay > maxRateLimitWait {
+			return fmt.Errorf("rate limit exhausted until %s", until.Format(time.RFC3339))
+		}
+		time.Sleep(delay)
+	}
+}
+
+// update records the quota reported by a response. Within the same window the
+// lower of the reported and locally reserved counts is kept, since other
+// workers' requests may still be in flight. A 304 from the cache doesn't
+// count against the quota, so its reservation is returned first.
+func (c *client) update(resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reset := c.reset
+	if value, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		reset = time.Unix(value, 0)
+	}
+	if limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit")); err == nil {
+		c.limit = limit
+	}
+
+	local := c.remaining
+	if local >= 0 && resp.StatusCode == http.StatusNotModified {
+		local++
+	}
+	if local < 0 || !reset.Equal(c.reset) || remaining < local {
+		local = remaining
+	}
+	c.remaining = local
+	c.reset = reset
+}
+
+// backoff reports how long to wait before retrying a 403/429 response, and
+// whether the response was a rate limit at all rather than a permission error.
+func (c *client) backoff(resp *http.Response) (time.Duration, bool) {
+	// Secondary rate limits tell us exactly how long to wait
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		c.mu.Lock()
+		reset := c.reset
+		c.mu.Unlock()
+		return time.Until(reset) + time.Second, true
+	}
+
+	// Secondary limit without Retry-After: GitHub recommends waiting at least a minute
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return time.Minute, true
+	}
+	return 0, false
+}
+
+func (c *client) pause(delay time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if until := time.Now().Add(delay); until.After(c.pausedUntil) {
+		c.pausedUntil = until
+	}
+}
+
+// quota returns the last observed remaining requests and limit, or -1 if no
+// response has reported them yet.
+func (c *client) quota() (int, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.remaining, c.limit
+}
+
+func cloneRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+// parseLinkHeader maps each rel of a pagination Link header to its URL, e.g.
+// <https://api.github.com/...&page=2>; rel="next".
+func parseLinkHeader(header string) map[string]string {
+	links := make(map[string]string)
+	for _, part := range strings.Split(header, ",") {
+		sections := strings.Split(part, ";")
+		if len(sections) < 2 {
+			continue
+		}
+
+		url := strings.Trim(strings.TrimSpace(sections[0]), "<>")
+		for _, param := range sections[1:] {
+			param = strings.TrimSpace(param)
+			if rel, ok := strings.CutPrefix(param, "rel="); ok {
+				for _, name := range strings.Fields(strings.Trim(rel, `"`)) {
+					links[name] = url
+				}
+			}
+		}
+	}
+	return links
+}
+
+// getJSON fetches a REST resource and decodes it into out.
+func getJSON(url string, out interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("User-Agent", "guitaripod-website")
+
+	resp, err := apiClient.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GitHub API responded with %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,115 @@
+package github
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestParseLinkHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   map[string]string
+	}{
+		{
+			name: "next and last",
+			header: `<https://api.github.com/user/repos?page=2>; rel="next", ` +
+				`<https://api.github.com/user/repos?page=5>; rel="last"`,
+			want: map[string]string{
+				"next": "https://api.github.com/user/repos?page=2",
+				"last": "https://api.github.com/user/repos?page=5",
+			},
+		},
+		{
+			name:   "several rels for one URL",
+			header: `<https://api.github.com/user/repos?page=1>; rel="first prev"`,
+			want: map[string]string{
+				"first": "https://api.github.com/user/repos?page=1",
+				"prev":  "https://api.github.com/user/repos?page=1",
+			},
+		},
+		{
+			name:   "unquoted rel and extra params",
+			header: `<https://api.github.com/x?page=3>; type="text/html"; rel=next`,
+			want:   map[string]string{"next": "https://api.github.com/x?page=3"},
+		},
+		{name: "empty", header: "", want: map[string]string{}},
+		{name: "no params", header: "<https://api.github.com/x>", want: map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLinkHeader(tt.header); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLinkHeader = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// rateLimited is a response reporting the given quota.
+func rateLimited(status, remaining int, reset time.Time) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header: http.Header{
+			"X-Ratelimit-Remaining": {strconv.Itoa(remaining)},
+			"X-Ratelimit-Limit":     {"5000"},
+			"X-Ratelimit-Reset":     {strconv.FormatInt(reset.Unix(), 10)},
+		},
+	}
+}
+
+func TestClientReservesQuota(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	c := &client{remaining: -1}
+
+	// Nothing is known before the first response
+	if err := c.wait(); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if remaining, _ := c.quota(); remaining != -1 {
+		t.Errorf("remaining before any response = %d, want -1", remaining)
+	}
+
+	c.update(rateLimited(http.StatusOK, 10, reset))
+	for i := 0; i < 3; i++ {
+		if err := c.wait(); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+	}
+	if remaining, _ := c.quota(); remaining != 7 {
+		t.Errorf("remaining after 3 reservations = %d, want 7", remaining)
+	}
+
+	// A late response from before the reservations doesn't raise the count
+	c.update(rateLimited(http.StatusOK, 9, reset))
+	if remaining, _ := c.quota(); remaining != 7 {
+		t.Errorf("remaining after a stale response = %d, want 7", remaining)
+	}
+
+	// A 304 hands its reservation back
+	c.update(rateLimited(http.StatusNotModified, 9, reset))
+	if remaining, _ := c.quota(); remaining != 8 {
+		t.Errorf("remaining after a 304 = %d, want 8", remaining)
+	}
+
+	// A new window takes the reported count as is
+	c.update(rateLimited(http.StatusOK, 4999, reset.Add(time.Hour)))
+	if remaining, _ := c.quota(); remaining != 4999 {
+		t.Errorf("remaining in a new window = %d, want 4999", remaining)
+	}
+}
+
+func TestClientStopsAtReserve(t *testing.T) {
+	c := &client{remaining: -1}
+	c.update(rateLimited(http.StatusOK, rateLimitReserve, time.Now().Add(time.Hour)))
+
+	if err := c.wait(); err == nil {
+		t.Error("wait returned no error with the quota at the reserve and the reset an hour away")
+	}
+	if remaining, _ := c.quota(); remaining != rateLimitReserve {
+		t.Errorf("remaining = %d, want %d untouched", remaining, rateLimitReserve)
+	}
+}
@@ -0,0 +1,151 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	commitCountExact     = "exact"
+	commitCountEstimated = "estimated"
+	commitCountUnknown   = "unknown"
+
+	// GitHub answers 202 while it computes repository statistics
+	statsAttempts = 4
+)
+
+// Wait between attempts while statistics are computed
+var statsDelay = 3 * time.Second
+
+var errStatsComputing = errors.New("statistics are still being computed")
+
+type contributorStats struct {
+	Total  int `json:"total"`
+	Author struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+// getCommitCount returns the number of commits on the default branch and
+// whether that number is exact, estimated or unknown.
+func getCommitCount(repo GitHubRepo) (int, string, error) {
+	count, err := countCommits(repo)
+	if err == nil {
+		return count, commitCountExact, nil
+	}
+
+	// Contributor statistics only cover the top 100 contributors
+	count, statsErr := sumContributorStats(repo)
+	if statsErr == nil {
+		return count, commitCountEstimated, nil
+	}
+
+	return 0, commitCountUnknown, fmt.Errorf("%v; %v", err, statsErr)
+}
+
+// countCommits requests a single commit per page so the page number of the
+// rel="last" link equals the total number of commits.
+func countCommits(repo GitHubRepo) (int, error) {
+	commitsURL := fmt.Sprintf("%s/repos/%s/commits?per_page=1", apiBaseURL, repo.fullName())
+
+	req, err := http.NewRequest("GET", commitsURL, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("User-Agent", "guitaripod-website")
+
+	resp, err := apiClient.do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// An empty repository has no commits to list
+	if resp.StatusCode == http.StatusConflict {
+		return 0, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("commits endpoint responded with %d", resp.StatusCode)
+	}
+
+	last, ok := parseLinkHeader(resp.Header.Get("Link"))["last"]
+	if !ok {
+		// Everything fit on the one page
+		var commits []json.RawMessage
+		if err := json.NewDecoder(resp.Body).Decode(&commits); err != nil {
+			return 0, err
+		}
+		return len(commits), nil
+	}
+
+	return pageNumber(last)
+}
+
+// sumContributorStats adds up the commits of every contributor, waiting for
+// GitHub to finish computing the statistics if needed.
+func sumContributorStats(repo GitHubRepo) (int, error) {
+	statsURL := fmt.Sprintf("%s/repos/%s/stats/contributors", apiBaseURL, repo.fullName())
+
+	for attempt := 1; attempt <= statsAttempts; attempt++ {
+		req, err := http.NewRequest("GET", statsURL, nil)
+		if err != nil {
+			return 0, err
+		}
+
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
+		req.Header.Set("User-Agent", "guitaripod-website")
+
+		resp, err := apiClient.do(req)
+		if err != nil {
+			return 0, err
+		}
+
+		if resp.StatusCode == http.StatusAccepted {
+			resp.Body.Close()
+			time.Sleep(statsDelay)
+			continue
+		}
+		if resp.StatusCode == http.StatusNoContent {
+			resp.Body.Close()
+			return 0, nil
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return 0, fmt.Errorf("contributor stats responded with %d", resp.StatusCode)
+		}
+
+		var stats []contributorStats
+		err = json.NewDecoder(resp.Body).Decode(&stats)
+		resp.Body.Close()
+		if err != nil {
+			return 0, err
+		}
+
+		total := 0
+		for _, s := range stats {
+			total += s.Total
+		}
+		return total, nil
+	}
+
+	return 0, errStatsComputing
+}
+
+func pageNumber(link string) (int, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return 0, err
+	}
+	page, err := strconv.Atoi(u.Query().Get("page"))
+	if err != nil {
+		return 0, fmt.Errorf("no page number in %s", link)
+	}
+	return page, nil
+}
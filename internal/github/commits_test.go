@@ -0,0 +1,127 @@
+package github
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGetCommitCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		routes  map[string]response
+		count   int
+		source  string
+		wantErr bool
+	}{
+		{
+			name: "page number of the last link",
+			routes: map[string]response{
+				"/repos/guitaripod/ct/commits?per_page=1": {
+					Header: map[string]string{"Link": `<https://api.github.com/repositories/1/commits?per_page=1&page=2>; rel="next", ` +
+						`<https://api.github.com/repositories/1/commits?per_page=1&page=1234>; rel="last"`},
+					Body: `[{"sha":"a"}]`,
+				},
+			},
+			count:  1234,
+			source: commitCountExact,
+		},
+		{
+			name: "single page",
+			routes: map[string]response{
+				"/repos/guitaripod/ct/commits?per_page=1": {Body: `[{"sha":"a"}]`},
+			},
+			count:  1,
+			source: commitCountExact,
+		},
+		{
+			name: "empty repository",
+			routes: map[string]response{
+				"/repos/guitaripod/ct/commits?per_page=1": {Status: http.StatusConflict, Body: `{"message":"Git Repository is empty."}`},
+			},
+			count:  0,
+			source: commitCountExact,
+		},
+		{
+			name: "contributor statistics fallback",
+			routes: map[string]response{
+				"/repos/guitaripod/ct/commits?per_page=1": {Status: http.StatusInternalServerError},
+				"/repos/guitaripod/ct/stats/contributors": {Body: `[{"total":40,"author":{"login":"guitaripod"}},{"total":2,"author":{"login":"someone"}}]`},
+			},
+			count:  42,
+			source: commitCountEstimated,
+		},
+		{
+			name: "statistics without content",
+			routes: map[string]response{
+				"/repos/guitaripod/ct/commits?per_page=1": {Status: http.StatusInternalServerError},
+				"/repos/guitaripod/ct/stats/contributors": {Status: http.StatusNoContent},
+			},
+			count:  0,
+			source: commitCountEstimated,
+		},
+		{
+			name: "both unavailable",
+			routes: map[string]response{
+				"/repos/guitaripod/ct/commits?per_page=1": {Status: http.StatusInternalServerError},
+				"/repos/guitaripod/ct/stats/contributors": {Status: http.StatusNotFound},
+			},
+			source:  commitCountUnknown,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeServer(t, tt.routes)
+
+			count, source, err := getCommitCount(GitHubRepo{FullName: "guitaripod/ct"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getCommitCount error = %v, want error %v", err, tt.wantErr)
+			}
+			if count != tt.count || source != tt.source {
+				t.Errorf("getCommitCount = %d, %s, want %d, %s", count, source, tt.count, tt.source)
+			}
+		})
+	}
+}
+
+func TestSumContributorStatsRetries(t *testing.T) {
+	delay := statsDelay
+	statsDelay = 0
+	t.Cleanup(func() { statsDelay = delay })
+
+	tests := []struct {
+		name     string
+		computed int32 // requests answered with 202 before the statistics are ready
+		count    int
+		wantErr  bool
+	}{
+		{name: "ready after retries", computed: statsAttempts - 1, count: 7},
+		{name: "still computing", computed: statsAttempts, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			fakeHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) <= tt.computed {
+					w.WriteHeader(http.StatusAccepted)
+					return
+				}
+				w.Write([]byte(`[{"total":7}]`))
+			}))
+
+			count, err := sumContributorStats(GitHubRepo{FullName: "guitaripod/ct"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sumContributorStats error = %v, want error %v", err, tt.wantErr)
+			}
+			if count != tt.count {
+				t.Errorf("sumContributorStats = %d, want %d", count, tt.count)
+			}
+			if got := requests.Load(); got > statsAttempts {
+				t.Errorf("made %d requests, want at most %d", got, statsAttempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	configPath        = "config/github.json"
+	defaultAPIURL     = "https://api.github.com"
+	defaultGraphQLURL = "https://api.github.com/graphql"
+)
+
+// GitHub endpoints, replaced by configureEndpoints for GitHub Enterprise
+// Server or a local stand-in. apiHost is the host[:port] requests are
+// authenticated for; webHost is the hostname credentials are stored under.
+var (
+	apiBaseURL = defaultAPIURL
+	graphQLURL = defaultGraphQLURL
+	apiHost    = "api.github.com"
+	webHost    = "github.com"
+)
+
+// Config selects which sources fetch-github reads beyond the user's own
+// repositories and which GitHub instance it talks to.
+type Config struct {
+	// Organizations whose public repositories are fetched alongside the
+	// user's own
+	Organizations []string `json:"organizations"`
+	// Contributions gathers merged pull requests to repositories the user
+	// doesn't own. Requires GitHub credentials.
+	Contributions bool `json:"contributions"`
+	// IncludeArchived keeps archived repositories, which are otherwise
+	// left out since they no longer accept changes
+	IncludeArchived bool `json:"includeArchived"`
+	// ServerURL is the web address of a GitHub Enterprise Server instance,
+	// from which the /api/v3 and /api/graphql endpoints are derived
+	ServerURL string `json:"serverURL,omitempty"`
+	// APIURL and GraphQLURL set the endpoints explicitly, e.g. for a local
+	// fake server
+	APIURL     string `json:"apiURL,omitempty"`
+	GraphQLURL string `json:"graphQLURL,omitempty"`
+	// Registries overrides the package registry base URLs
+	Registries RegistryConfig `json:"registries,omitempty"`
+	// HomebrewTaps are the owner/homebrew-name repositories whose formulae
+	// install the user's projects. They're never shown as projects themselves.
+	HomebrewTaps []string `json:"homebrewTaps"`
+}
+
+// isTap reports whether the repo is one of the configured Homebrew taps.
+func (c Config) isTap(repo GitHubRepo) bool {
+	for _, tap := range c.HomebrewTaps {
+		if strings.EqualFold(tap, repo.fullName()) {
+			return true
+		}
+	}
+	return false
+}
+
+func loadConfig(path string) (Config, error) {
+	var config Config
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	// Same variables GitHub Actions sets, so workflows on Enterprise Server
+	// pick up their instance automatically
+	for env, field := range map[string]*string{
+		"GITHUB_SERVER_URL":  &config.ServerURL,
+		"GITHUB_API_URL":     &config.APIURL,
+		"GITHUB_GRAPHQL_URL": &config.GraphQLURL,
+	} {
+		if value := os.Getenv(env); value != "" {
+			*field = value
+		}
+	}
+
+	if err := configureEndpoints(config); err != nil {
+		return config, fmt.Errorf("invalid endpoint in %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// configureEndpoints resolves the REST and GraphQL URLs. Explicit URLs win;
+// otherwise they are derived from the server URL, with github.com mapping to
+// api.github.com and any other host following the Enterprise Server layout.
+func configureEndpoints(config Config) error {
+	rest := strings.TrimSuffix(config.APIURL, "/")
+	graphql := strings.TrimSuffix(config.GraphQLURL, "/")
+
+	if rest == "" && config.ServerURL != "" {
+		server, err := parseEndpoint(config.ServerURL)
+		if err != nil {
+			return err
+		}
+		if server.Hostname() != "github.com" {
+			rest = strings.TrimSuffix(server.String(), "/") + "/api/v3"
+		}
+	}
+	if rest == "" {
+		rest = defaultAPIURL
+	}
+
+	if graphql == "" {
+		switch {
+		case rest == defaultAPIURL:
+			graphql = defaultGraphQLURL
+		case strings.HasSuffix(rest, "/api/v3"):
+			graphql = strings.TrimSuffix(rest, "/v3") + "/graphql"
+		default:
+			graphql = rest + "/graphql"
+		}
+	}
+
+	restURL, err := parseEndpoint(rest)
+	if err != nil {
+		return err
+	}
+	if _, err := parseEndpoint(graphql); err != nil {
+		return err
+	}
+
+	apiBaseURL, graphQLURL = rest, graphql
+	apiHost = restURL.Host
+	webHost = restURL.Hostname()
+	if webHost == "api.github.com" {
+		webHost = "github.com"
+	}
+
+	if apiBaseURL != defaultAPIURL {
+		fmt.Printf("Using GitHub API at %s (GraphQL: %s)\n", apiBaseURL, graphQLURL)
+	}
+	return nil
+}
+
+func parseEndpoint(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%q is not an http(s) URL", raw)
+	}
+	return u, nil
+}
@@ -0,0 +1,88 @@
+package github
+
+import "testing"
+
+func TestConfigureEndpoints(t *testing.T) {
+	api, graphql, host, web := apiBaseURL, graphQLURL, apiHost, webHost
+	t.Cleanup(func() {
+		apiBaseURL, graphQLURL, apiHost, webHost = api, graphql, host, web
+	})
+
+	tests := []struct {
+		name    string
+		config  Config
+		rest    string
+		graphql string
+		apiHost string
+		webHost string
+	}{
+		{
+			name:    "github.com by default",
+			rest:    "https://api.github.com",
+			graphql: "https://api.github.com/graphql",
+			apiHost: "api.github.com",
+			webHost: "github.com",
+		},
+		{
+			name:    "github.com server URL",
+			config:  Config{ServerURL: "https://github.com/"},
+			rest:    "https://api.github.com",
+			graphql: "https://api.github.com/graphql",
+			apiHost: "api.github.com",
+			webHost: "github.com",
+		},
+		{
+			name:    "Enterprise Server",
+			config:  Config{ServerURL: "https://git.example.com"},
+			rest:    "https://git.example.com/api/v3",
+			graphql: "https://git.example.com/api/graphql",
+			apiHost: "git.example.com",
+			webHost: "git.example.com",
+		},
+		{
+			name:    "explicit URLs win",
+			config:  Config{ServerURL: "https://git.example.com", APIURL: "http://localhost:8080/", GraphQLURL: "http://localhost:8081/gql"},
+			rest:    "http://localhost:8080",
+			graphql: "http://localhost:8081/gql",
+			apiHost: "localhost:8080",
+			webHost: "localhost",
+		},
+		{
+			name:    "GraphQL next to a custom REST URL",
+			config:  Config{APIURL: "http://127.0.0.1:9000/api"},
+			rest:    "http://127.0.0.1:9000/api",
+			graphql: "http://127.0.0.1:9000/api/graphql",
+			apiHost: "127.0.0.1:9000",
+			webHost: "127.0.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := configureEndpoints(tt.config); err != nil {
+				t.Fatalf("configureEndpoints: %v", err)
+			}
+			if apiBaseURL != tt.rest || graphQLURL != tt.graphql || apiHost != tt.apiHost || webHost != tt.webHost {
+				t.Errorf("endpoints = %s %s %s %s, want %s %s %s %s",
+					apiBaseURL, graphQLURL, apiHost, webHost, tt.rest, tt.graphql, tt.apiHost, tt.webHost)
+			}
+		})
+	}
+}
+
+func TestConfigureEndpointsRejectsInvalidURLs(t *testing.T) {
+	api, graphql, host, web := apiBaseURL, graphQLURL, apiHost, webHost
+	t.Cleanup(func() {
+		apiBaseURL, graphQLURL, apiHost, webHost = api, graphql, host, web
+	})
+
+	for _, config := range []Config{
+		{ServerURL: "git.example.com"},
+		{APIURL: "ftp://api.example.com"},
+		{GraphQLURL: "/graphql"},
+	} {
+		if err := configureEndpoints(config); err == nil {
+			t.Errorf("configureEndpoints(%+v) returned no error", config)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package github
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const contributionsQuery = `query($query: String!, $cursor: String) {
+	search(query: $query, type: ISSUE, first: 100, after: $cursor) {
+		pageInfo {
+			hasNextPage
+			endCursor
+		}
+		nodes {
+			... on PullRequest {
+				number
+				title
+				url
+				mergedAt
+				repository {
+					nameWithOwner
+					url
+					description
+					stargazerCount
+					primaryLanguage {
+						name
+					}
+				}
+			}
+		}
+	}
+}`
+
+// Contribution groups the merged pull requests made to one repository the
+// user doesn't own.
+type Contribution struct {
+	Repo         string        `json:"repo"` // owner/name
+	URL          string        `json:"url"`
+	Description  string        `json:"description"`
+	Language     string        `json:"language"`
+	Stars        int           `json:"stars"`
+	PullRequests []PullRequest `json:"pullRequests"`
+}
+
+type PullRequest struct {
+	Number   int    `json:"number"`
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	MergedAt string `json:"mergedAt"`
+}
+
+type contributionsResponse struct {
+	Search struct {
+		PageInfo struct {
+			HasNextPage bool   `json:"hasNextPage"`
+			EndCursor   string `json:"endCursor"`
+		} `json:"pageInfo"`
+		Nodes []struct {
+			Number     int    `json:"number"`
+			Title      string `json:"title"`
+			URL        string `json:"url"`
+			MergedAt   string `json:"mergedAt"`
+			Repository struct {
+				NameWithOwner   string `json:"nameWithOwner"`
+				URL             string `json:"url"`
+				Description     string `json:"description"`
+				StargazerCount  int    `json:"stargazerCount"`
+				PrimaryLanguage *struct {
+					Name string `json:"name"`
+				} `json:"primaryLanguage"`
+			} `json:"repository"`
+		} `json:"nodes"`
+	} `json:"search"`
+}
+
+// contributionsSearch finds merged public pull requests authored by the user
+// outside their own repos and the configured organizations, which are
+// already covered as projects.
+func contributionsSearch(organizations []string) string {
+	terms := []string{"is:pr", "is:merged", "is:public", "author:" + githubUsername, "-user:" + githubUsername}
+	for _, org := range organizations {
+		terms = append(terms, "-org:"+org)
+	}
+	return strings.Join(terms, " ")
+}
+
+// fetchContributions pages through the search results, grouping pull
+// requests by repository. Repositories are ordered by stars, then by the
+// number of merged pull requests. GraphQL requires credentials.
+func fetchContributions(organizations []string) ([]Contribution, error) {
+	byRepo := make(map[string]*Contribution)
+	var cursor *string
+
+	for {
+		var data contributionsResponse
+		variables := map[string]interface{}{
+			"query":  contributionsSearch(organizations),
+			"cursor": cursor,
+		}
+		if err := graphQL(contributionsQuery, variables, &data); err != nil {
+			return nil, err
+		}
+
+		for _, node := range data.Search.Nodes {
+			// Nodes of other types decode empty
+			if node.Repository.NameWithOwner == "" {
+				continue
+			}
+
+			repo := node.Repository
+			contribution, ok := byRepo[repo.NameWithOwner]
+			if !ok {
+				contribution = &Contribution{
+					Repo:         repo.NameWithOwner,
+					URL:          repo.URL,
+					Description:  repo.Description,
+					Stars:        repo.StargazerCount,
+					PullRequests: []PullRequest{},
+				}
+				if repo.PrimaryLanguage != nil {
+					contribution.Language = repo.PrimaryLanguage.Name
+				}
+				byRepo[repo.NameWithOwner] = contribution
+			}
+			contribution.PullRequests = append(contribution.PullRequests, PullRequest{
+				Number:   node.Number,
+				Title:    node.Title,
+				URL:      node.URL,
+				MergedAt: node.MergedAt,
+			})
+		}
+
+		pageInfo := data.Search.PageInfo
+		if !pageInfo.HasNextPage {
+			break
+		}
+		endCursor := pageInfo.EndCursor
+		cursor = &endCursor
+	}
+
+	contributions := make([]Contribution, 0, len(byRepo))
+	for _, c := range byRepo {
+		// RFC 3339 timestamps sort chronologically as strings
+		sort.Slice(c.PullRequests, func(i, j int) bool {
+			return c.PullRequests[i].MergedAt > c.PullRequests[j].MergedAt
+		})
+		contributions = append(contributions, *c)
+	}
+	sort.Slice(contributions, func(i, j int) bool {
+		if contributions[i].Stars != contributions[j].Stars {
+			return contributions[i].Stars > contributions[j].Stars
+		}
+		if len(contributions[i].PullRequests) != len(contributions[j].PullRequests) {
+			return len(contributions[i].PullRequests) > len(contributions[j].PullRequests)
+		}
+		return contributions[i].Repo < contributions[j].Repo
+	})
+
+	fmt.Printf("Found %d merged pull requests across %d repositories\n", countPullRequests(contributions), len(contributions))
+	return contributions, nil
+}
+
+func countPullRequests(contributions []Contribution) int {
+	total := 0
+	for _, c := range contributions {
+		total += len(c.PullRequests)
+	}
+	return total
+}
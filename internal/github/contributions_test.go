@@ -0,0 +1,93 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func pullRequestNode(repo string, stars, number int, mergedAt string) string {
+	return fmt.Sprintf(`{"number":%d,"title":"PR %d","url":"https://github.com/%s/pull/%d","mergedAt":%q,
+		"repository":{"nameWithOwner":%q,"url":"https://github.com/%s","stargazerCount":%d,"primaryLanguage":{"name":"Go"}}}`,
+		number, number, repo, number, mergedAt, repo, repo, stars)
+}
+
+func TestFetchContributions(t *testing.T) {
+	pages := map[string]string{
+		"": `{"data":{"search":{"pageInfo":{"hasNextPage":true,"endCursor":"next"},"nodes":[` +
+			pullRequestNode("small/tool", 10, 1, "2025-01-01T00:00:00Z") + `,` +
+			pullRequestNode("big/framework", 900, 7, "2025-01-05T00:00:00Z") + `,` +
+			`{},` +
+			pullRequestNode("tie/b", 10, 3, "2025-02-01T00:00:00Z") + `]}}}`,
+		"next": `{"data":{"search":{"pageInfo":{"hasNextPage":false},"nodes":[` +
+			pullRequestNode("big/framework", 900, 9, "2025-03-01T00:00:00Z") + `,` +
+			pullRequestNode("tie/a", 10, 4, "2025-02-02T00:00:00Z") + `,` +
+			pullRequestNode("tie/a", 10, 2, "2025-01-20T00:00:00Z") + `]}}}`,
+	}
+
+	var searches []string
+	srv := fakeHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Variables struct {
+				Query  string  `json:"query"`
+				Cursor *string `json:"cursor"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		searches = append(searches, body.Variables.Query)
+
+		cursor := ""
+		if body.Variables.Cursor != nil {
+			cursor = *body.Variables.Cursor
+		}
+		fmt.Fprint(w, pages[cursor])
+	}))
+	url := graphQLURL
+	graphQLURL = srv.URL + "/graphql"
+	t.Cleanup(func() { graphQLURL = url })
+
+	contributions, err := fetchContributions([]string{"acme"})
+	if err != nil {
+		t.Fatalf("fetchContributions: %v", err)
+	}
+
+	if len(searches) != 2 {
+		t.Fatalf("made %d searches, want 2", len(searches))
+	}
+	if want := "is:pr is:merged is:public author:guitaripod -user:guitaripod -org:acme"; searches[0] != want {
+		t.Errorf("search = %q, want %q", searches[0], want)
+	}
+
+	type group struct {
+		repo    string
+		numbers []int
+	}
+	var got []group
+	for _, c := range contributions {
+		g := group{repo: c.Repo}
+		for _, pr := range c.PullRequests {
+			g.numbers = append(g.numbers, pr.Number)
+		}
+		got = append(got, g)
+	}
+	// Stars first, then pull request count, then name; newest merge first
+	want := []group{
+		{"big/framework", []int{9, 7}},
+		{"tie/a", []int{4, 2}},
+		{"small/tool", []int{1}},
+		{"tie/b", []int{3}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("contributions = %v, want %v", got, want)
+	}
+	if contributions[0].Language != "Go" || contributions[0].Stars != 900 {
+		t.Errorf("repository details = %+v", contributions[0])
+	}
+	if n := countPullRequests(contributions); n != 6 {
+		t.Errorf("countPullRequests = %d, want 6", n)
+	}
+}
@@ -0,0 +1,94 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// deps.dev system names of the registries it tracks. Swift packages aren't
+// covered and crates use crates.io's own reverse dependencies.
+var depsDevSystems = map[string]string{
+	"go":  "GO",
+	"npm": "NPM",
+}
+
+// countsDependents reports whether the package's registry has a source of
+// reverse dependencies. deps.dev needs the published version.
+func countsDependents(pkg Package) bool {
+	if pkg.Registry == "crates" {
+		return true
+	}
+	_, ok := depsDevSystems[pkg.Registry]
+	return ok && pkg.Version != ""
+}
+
+// getPackageDependents counts the packages in the same registry that depend
+// on pkg directly. It returns 0 for registries without reverse dependencies.
+func getPackageDependents(pkg Package, config RegistryConfig) (int, error) {
+	config = config.withDefaults()
+
+	if pkg.Registry == "crates" {
+		var data struct {
+			Meta struct {
+				Total int `json:"total"`
+			} `json:"meta"`
+		}
+		dependentsURL := fmt.Sprintf("%s/api/v1/crates/%s/reverse_dependencies?per_page=1", config.CratesURL, url.PathEscape(pkg.Name))
+		if _, err := registryJSON(dependentsURL, &data); err != nil {
+			return 0, err
+		}
+		return data.Meta.Total, nil
+	}
+
+	system, ok := depsDevSystems[pkg.Registry]
+	if !ok || pkg.Version == "" {
+		return 0, nil
+	}
+	var data struct {
+		DirectDependentCount int `json:"directDependentCount"`
+	}
+	dependentsURL := fmt.Sprintf("%s/v3alpha/systems/%s/packages/%s/versions/%s:dependents",
+		config.DepsDevURL, system, url.PathEscape(pkg.Name), url.PathEscape(pkg.Version))
+	if _, err := registryJSON(dependentsURL, &data); err != nil {
+		return 0, err
+	}
+	return data.DirectDependentCount, nil
+}
+
+// addDependents sums each project's dependents across the registries it
+// publishes to. Packages that can't be counted, such as Swift packages, are
+// marked unknown rather than counted as 0, and so is a project none of whose
+// packages could be counted. Projects without packages are skipped, and
+// projects carried from the previous run keep their counts.
+func addDependents(projects []Project, config RegistryConfig) {
+	parallel(len(projects), func(i int) {
+		project := &projects[i]
+		if project.carried || len(project.Packages) == 0 {
+			return
+		}
+
+		counted := false
+		for j := range project.Packages {
+			pkg := &project.Packages[j]
+			if !countsDependents(*pkg) {
+				pkg.DependentsUnknown = true
+				continue
+			}
+
+			count, err := getPackageDependents(*pkg, config)
+			if err != nil {
+				fmt.Printf("  ⚠️  %s: couldn't count %s dependents: %v\n", project.Name, pkg.Registry, err)
+				pkg.DependentsUnknown = true
+				continue
+			}
+			pkg.Dependents = count
+			project.Dependents += count
+			counted = true
+		}
+		project.DependentsUnknown = !counted
+
+		if project.Dependents > 0 {
+			fmt.Printf("  🔗 %s: used by %d packages\n", project.Name, project.Dependents)
+		}
+	})
+}
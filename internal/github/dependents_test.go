@@ -0,0 +1,41 @@
+package github
+
+import "testing"
+
+func TestAddDependents(t *testing.T) {
+	srv := fakeServer(t, map[string]response{
+		"/api/v1/crates/nasa-rs/reverse_dependencies?per_page=1":                               {Body: `{"dependencies":[],"versions":[],"meta":{"total":4}}`},
+		"/v3alpha/systems/NPM/packages/widget/versions/2.0.0:dependents":                       {Body: `{"dependentCount":9,"directDependentCount":3}`},
+		"/api/v1/crates/broken/reverse_dependencies?per_page=1":                                {Status: 500},
+		"/v3alpha/systems/GO/packages/github.com%2Fguitaripod%2Fct/versions/v1.0.0:dependents": {Body: `{"directDependentCount":0}`},
+	})
+
+	projects := []Project{
+		{Name: "nasa-rs", Packages: []Package{{Registry: "crates", Name: "nasa-rs"}, {Registry: "npm", Name: "widget", Version: "2.0.0"}}},
+		{Name: "ct", Packages: []Package{{Registry: "go", Name: "github.com/guitaripod/ct", Version: "v1.0.0"}}},
+		{Name: "GeminiKit", Packages: []Package{{Registry: "swift", Name: "guitaripod/GeminiKit"}}},
+		{Name: "mixed", Packages: []Package{{Registry: "crates", Name: "nasa-rs"}, {Registry: "swift", Name: "guitaripod/NASAKit"}}},
+		{Name: "failing", Packages: []Package{{Registry: "crates", Name: "broken"}}},
+		{Name: "carried", Dependents: 7, Packages: []Package{{Registry: "crates", Name: "gone"}}, carried: true},
+		{Name: "unpublished", Packages: []Package{}},
+	}
+	addDependents(projects, fakeRegistries(srv.URL))
+
+	want := map[string]int{"nasa-rs": 7, "ct": 0, "GeminiKit": 0, "mixed": 4, "failing": 0, "carried": 7, "unpublished": 0}
+	unknown := map[string]bool{"GeminiKit": true, "failing": true}
+	for _, p := range projects {
+		if p.Dependents != want[p.Name] {
+			t.Errorf("%s: Dependents = %d, want %d", p.Name, p.Dependents, want[p.Name])
+		}
+		if p.DependentsUnknown != unknown[p.Name] {
+			t.Errorf("%s: DependentsUnknown = %v, want %v", p.Name, p.DependentsUnknown, unknown[p.Name])
+		}
+	}
+	if got := projects[0].Packages[1].Dependents; got != 3 {
+		t.Errorf("npm package Dependents = %d, want 3", got)
+	}
+	// A counted package next to a Swift package keeps the project known
+	if swift := projects[3].Packages[1]; !swift.DependentsUnknown {
+		t.Errorf("Swift package of a mixed project isn't marked unknown")
+	}
+}
@@ -9,194 +9,357 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
 const (
 	githubUsername = "guitaripod"
-	githubAPIURL   = "https://api.github.com/users/%s/repos?per_page=100&sort=updated"
-	graphQLURL     = "https://api.github.com/graphql"
+	githubAPIURL   = "%s/users/%s/repos?per_page=100&sort=updated"
+	githubOrgURL   = "%s/orgs/%s/repos?per_page=100&type=public&sort=updated"
+	fetchWorkers   = 8
 )
 
 var hasGitHubToken bool
 
 var excludeRepos = []string{
-	"guitaripod",                   // profile repo
+	"guitaripod",                    // profile repo
 	"isowords",                      // fork
 	"swift-composable-architecture", // fork
-	"homebrew-apod-cli",             // homebrew tap
-	"homebrew-songlink-cli",         // homebrew tap
 }
 
 type GitHubRepo struct {
-	Name            string    `json:"name"`
-	Description     string    `json:"description"`
-	Language        string    `json:"language"`
-	Fork            bool      `json:"fork"`
-	Private         bool      `json:"private"`
-	StargazersCount int       `json:"stargazers_count"`
-	HTMLURL         string    `json:"html_url"`
-	UpdatedAt       string    `json:"updated_at"`
-	CreatedAt       string    `json:"created_at"`
-	Topics          []string  `json:"topics"`
-	HomepageURL     string    `json:"homepage"`
+	Name            string   `json:"name"`
+	FullName        string   `json:"full_name"`
+	Description     string   `json:"description"`
+	Language        string   `json:"language"`
+	Fork            bool     `json:"fork"`
+	Private         bool     `json:"private"`
+	Archived        bool     `json:"archived"`
+	StargazersCount int      `json:"stargazers_count"`
+	ForksCount      int      `json:"forks_count"`
+	OpenIssuesCount int      `json:"open_issues_count"` // includes pull requests
+	License         *License `json:"license"`
+	DefaultBranch   string   `json:"default_branch"`
+	HTMLURL         string   `json:"html_url"`
+	UpdatedAt       string   `json:"updated_at"`
+	CreatedAt       string   `json:"created_at"`
+	PushedAt        string   `json:"pushed_at"`
+	Topics          []string `json:"topics"`
+	HomepageURL     string   `json:"homepage"`
 }
 
-type Contributor struct {
-	Login         string `json:"login"`
-	Contributions int    `json:"contributions"`
+// fullName returns owner/name, assuming the user owns repos that were stored
+// before the owner was recorded.
+func (r GitHubRepo) fullName() string {
+	if r.FullName != "" {
+		return r.FullName
+	}
+	return githubUsername + "/" + r.Name
+}
+
+func (r GitHubRepo) owner() string {
+	owner, _, _ := strings.Cut(r.fullName(), "/")
+	return owner
+}
+
+// projectID is the lowercase repo name for the user's own repos and the
+// lowercase owner/name for organization repos, so the two can't collide.
+func (r GitHubRepo) projectID() string {
+	if strings.EqualFold(r.owner(), githubUsername) {
+		return strings.ToLower(r.Name)
+	}
+	return strings.ToLower(r.fullName())
+}
+
+type repoMetrics struct {
+	GitHubRepo
+	CommitCount       int
+	CommitCountSource string
+	ReleaseCount      int
+	LatestRelease     *Release
+	Downloads         int
+	Languages         []LanguageShare
+	CIStatus          string
+	Assets            []string // file names of the latest release
+	Platforms         []string
+	Architectures     []string
+	carried           bool // reused from the previous run by --incremental
 }
 
 type Project struct {
-	ID           string   `json:"id"`
-	Name         string   `json:"name"`
-	Description  string   `json:"description"`
-	Language     string   `json:"language"`
-	Platforms    []string `json:"platforms"`
-	Stars        int      `json:"stars"`
-	GitHubURL    string   `json:"githubUrl"`
-	Category     string   `json:"category"`
-	Highlights   []string `json:"highlights"`
-	UpdatedAt    string   `json:"updatedAt"`
-	CreatedAt    string   `json:"createdAt"`
-	Topics       []string `json:"topics"`
-	CommitCount  int      `json:"commitCount"`
-	ReleaseCount int      `json:"releaseCount"`
-	HomepageURL  string   `json:"homepageUrl,omitempty"`
+	ID                string           `json:"id"`
+	Name              string           `json:"name"`
+	Owner             string           `json:"owner"`
+	Description       string           `json:"description"`
+	Language          string           `json:"language"`
+	Languages         []LanguageShare  `json:"languages"`
+	Platforms         []string         `json:"platforms"`
+	Architectures     []string         `json:"architectures"`
+	Stars             int              `json:"stars"`
+	Pinned            bool             `json:"pinned"`
+	PinOrder          int              `json:"pinOrder,omitempty"` // 1-based position on the GitHub profile
+	Forks             int              `json:"forks"`
+	OpenIssues        int              `json:"openIssues"`
+	StarsGained30d    int              `json:"starsGained30d"`
+	TrendingScore     float64          `json:"trendingScore"`
+	GitHubURL         string           `json:"githubUrl"`
+	Category          string           `json:"category"`
+	Highlights        []string         `json:"highlights"`
+	UpdatedAt         string           `json:"updatedAt"`
+	CreatedAt         string           `json:"createdAt"`
+	PushedAt          string           `json:"pushedAt"`
+	Topics            []string         `json:"topics"`
+	Archived          bool             `json:"archived"`
+	License           string           `json:"license,omitempty"` // SPDX identifier where GitHub recognizes it
+	Health            Health           `json:"health"`
+	CommitCount       int              `json:"commitCount"`
+	CommitCountSource string           `json:"commitCountSource"` // exact, estimated or unknown
+	ReleaseCount      int              `json:"releaseCount"`
+	LatestRelease     *Release         `json:"latestRelease,omitempty"`
+	Downloads         int              `json:"downloads"`
+	HomepageURL       string           `json:"homepageUrl,omitempty"`
+	Homebrew          *HomebrewFormula `json:"homebrew,omitempty"`
+	Readme            *ReadmeInfo      `json:"readme,omitempty"`
+	Packages          []Package        `json:"packages"`
+	Dependents        int              `json:"dependents"`
+	DependentsUnknown bool             `json:"dependentsUnknown,omitempty"` // no package's dependents could be counted
+
+	carried bool // registry, dependents and README details came from the previous run
 }
 
 type OpenSourceData struct {
-	LastUpdated string    `json:"lastUpdated"`
-	TotalRepos  int       `json:"totalRepos"`
-	Projects    []Project `json:"projects"`
+	LastUpdated   string          `json:"lastUpdated"`
+	TotalRepos    int             `json:"totalRepos"`
+	Languages     []LanguageShare `json:"languages"`
+	Projects      []Project       `json:"projects"`
+	Contributions []Contribution  `json:"contributions"`
 }
 
-type GraphQLQuery struct {
-	Query string `json:"query"`
+// FetchOptions controls how the GitHub data is refreshed and ordered.
+type FetchOptions struct {
+	// Incremental reuses metrics from the existing opensource.json for repos
+	// that haven't been pushed or updated since it was written.
+	Incremental bool
+	// Sort orders the featured projects after the pinned ones by
+	// SortPinned (default, by stars), SortStars, SortTrending, SortRecent
+	// or SortDependents
+	Sort string
+	// Record appends today's metrics to the history file
+	Record bool
 }
 
-type PinnedReposResponse struct {
-	Data struct {
-		User struct {
-			PinnedItems struct {
-				Nodes []struct {
-					Name string `json:"name"`
-				} `json:"nodes"`
-			} `json:"pinnedItems"`
-		} `json:"user"`
-	} `json:"data"`
+const (
+	SortPinned     = "pinned"
+	SortStars      = "stars"
+	SortTrending   = "trending"
+	SortRecent     = "recent"
+	SortDependents = "dependents"
+)
+
+type GraphQLQuery struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
 }
 
-func FetchData() error {
+func FetchData(opts FetchOptions) error {
 	fmt.Println("Fetching latest GitHub repository data...")
+
+	switch opts.Sort {
+	case "":
+		opts.Sort = SortPinned
+	case SortPinned, SortStars, SortTrending, SortRecent, SortDependents:
+	default:
+		return fmt.Errorf("unknown sort %q: expected %s, %s, %s, %s or %s",
+			opts.Sort, SortPinned, SortStars, SortTrending, SortRecent, SortDependents)
+	}
 	
-	// Check if GitHub token is set
-	token := os.Getenv("GITHUB_TOKEN")
-	hasGitHubToken = token != ""
-	
-	if !hasGitHubToken {
-		fmt.Println("⚠️  WARNING: GITHUB_TOKEN not set. API rate limits will be very restrictive.")
-		fmt.Println("   Set GITHUB_TOKEN environment variable to increase rate limits.")
-		fmt.Println("   Using longer delays to avoid rate limiting...")
-	} else {
-		fmt.Println("✓ GitHub token detected")
+	rules, err := loadRules(rulesPath)
+	if err != nil {
+		return err
 	}
-
-	// Fetch pinned repos first
-	pinnedRepos, err := fetchPinnedRepos()
+	overrides, err := loadOverrides(overridesPath)
 	if err != nil {
-		fmt.Printf("Warning: failed to fetch pinned repos: %v\n", err)
-		pinnedRepos = []string{} // Continue without pinned repos
+		return err
 	}
-
-	repos, err := fetchGitHubRepos()
+	config, err := loadConfig(configPath)
 	if err != nil {
-		return fmt.Errorf("failed to fetch GitHub repos: %w", err)
+		return err
 	}
 
-	// Filter repositories by release status
-	fmt.Println("Checking repositories for releases...")
-	fmt.Printf("Note: This may take several minutes due to rate limit protection\n")
-	fmt.Printf("Processing %d repositories...\n", len(repos))
-	var reposWithMetrics []struct {
-		GitHubRepo
-		CommitCount  int
-		ReleaseCount int
+	if hasGitHubToken, err = authenticate(); err != nil {
+		return err
 	}
 
-	for i, repo := range repos {
-		// Skip if it's in the exclude list or doesn't meet basic criteria
-		if repo.Fork || repo.Private || contains(excludeRepos, repo.Name) ||
-			repo.Description == "" {
-			continue
+	outputPath := filepath.Join("src", "data", "opensource.json")
+
+	var previous *previousData
+	if opts.Incremental {
+		var err error
+		previous, err = loadPreviousData(snapshotPath)
+		if err != nil {
+			fmt.Printf("Warning: incremental refresh unavailable, fetching everything: %v\n", err)
+		} else {
+			fmt.Printf("Incremental refresh: reusing metrics for repos unchanged since %s\n",
+				previous.lastUpdated.Format(time.RFC3339))
 		}
+	}
 
-		// Add significant delay between API calls to avoid rate limiting
-		if i > 0 {
-			delay := 3 * time.Second
-			if !hasGitHubToken {
-				delay = 10 * time.Second // Much longer delay without token
+	var (
+		reposWithMetrics []repoMetrics
+		pinnedRepos      []string
+		totalRepos       int
+		usedGraphQL      bool
+	)
+
+	// A single paginated GraphQL query covers everything when authenticated
+	if hasGitHubToken {
+		repos, pinned, total, err := fetchRepositoriesGraphQL()
+		if err != nil {
+			fmt.Printf("Warning: GraphQL query failed, falling back to REST: %v\n", err)
+		} else {
+			fmt.Printf("Fetched %d repositories via GraphQL\n", len(repos))
+			for _, org := range config.Organizations {
+				orgRepos, err := fetchOrganizationRepositoriesGraphQL(org)
+				if err != nil {
+					fmt.Printf("Warning: failed to fetch %s repositories: %v\n", org, err)
+					continue
+				}
+				fmt.Printf("Fetched %d repositories from %s via GraphQL\n", len(orgRepos), org)
+				repos = append(repos, orgRepos...)
+				total += len(orgRepos)
+			}
+			reposWithMetrics = filterReleased(repos, pinned, overrides, config)
+			var carried []repoMetrics
+			if previous != nil {
+				reposWithMetrics, carried = previous.carry(reposWithMetrics)
 			}
-			fmt.Printf("  ⏳ Waiting %v before checking %s...\n", delay, repo.Name)
-			time.Sleep(delay)
+			addDownloads(reposWithMetrics)
+			reposWithMetrics = append(carried, reposWithMetrics...)
+			pinnedRepos = pinned
+			totalRepos = total
+			usedGraphQL = true
 		}
+	}
+
+	// Pinned repos only come from GraphQL. Keep the last known ones so the
+	// page order doesn't change whenever the query is unavailable.
+	if !usedGraphQL {
+		pinnedRepos = previousPinned(outputPath)
+		fmt.Printf("Warning: pinned repositories need GraphQL, reusing %d from %s\n", len(pinnedRepos), outputPath)
+	}
 
-		// Fetch release count
-		releaseCount, err := getReleaseCount(repo)
+	if !usedGraphQL {
+		repos, err := fetchGitHubRepos()
 		if err != nil {
-			fmt.Printf("  ✗ %s: error fetching releases: %v\n", repo.Name, err)
-			continue
+			return fmt.Errorf("failed to fetch GitHub repos: %w", err)
 		}
-
-		// Simple filtering: must have at least 1 release
-		if releaseCount < 1 {
-			fmt.Printf("  ✗ %s: no releases\n", repo.Name)
-			continue
+		for _, org := range config.Organizations {
+			orgRepos, err := fetchOrganizationRepos(org)
+			if err != nil {
+				fmt.Printf("Warning: failed to fetch %s repositories: %v\n", org, err)
+				continue
+			}
+			fmt.Printf("Fetched %d repositories from %s\n", len(orgRepos), org)
+			repos = append(repos, orgRepos...)
 		}
-
-		// Now fetch commit count for metadata (not for filtering)
-		apiDelay := 2 * time.Second
-		if !hasGitHubToken {
-			apiDelay = 5 * time.Second
+		totalRepos = len(repos)
+
+		// Filter repositories by release status
+		fmt.Println("Checking repositories for releases...")
+		var candidates []GitHubRepo
+		for _, repo := range repos {
+			if overrides.hidden(repo.projectID()) || config.isTap(repo) {
+				continue
+			}
+			if isCandidate(repo, config.IncludeArchived) || overrides.included(repo.projectID()) {
+				candidates = append(candidates, repo)
+			}
 		}
-		time.Sleep(apiDelay)
-		
-		commitCount, err := getCommitCount(repo)
-		if err != nil {
-			// Use 0 if we can't get commit count, but don't skip the repo
-			commitCount = 0
-			fmt.Printf("  ⚠️  %s: couldn't fetch commit count, using 0\n", repo.Name)
+
+		var carried []repoMetrics
+		if previous != nil {
+			candidates, carried = previous.split(candidates, overrides)
 		}
 
-		// Add to results - we already know it has releases
-		reposWithMetrics = append(reposWithMetrics, struct {
-			GitHubRepo
-			CommitCount  int
-			ReleaseCount int
-		}{repo, commitCount, releaseCount})
-		fmt.Printf("  ✓ %s: %d commits, %d releases, %d stars (released project)\n", 
-			repo.Name, commitCount, releaseCount, repo.StargazersCount)
+		fmt.Printf("Processing %d repositories with %d workers...\n", len(candidates), workerCount())
+
+		reposWithMetrics = append(carried, collectMetrics(candidates, overrides)...)
+
+		// GraphQL reads the check status along with everything else
+		fmt.Println("Reading CI status of default branches...")
+		addCIStatus(reposWithMetrics)
+	}
+
+	// Manifests and release assets say where each project actually runs
+	fmt.Println("Detecting platforms from manifests and release assets...")
+	addPlatforms(reposWithMetrics)
+
+	if remaining, limit := apiClient.quota(); remaining >= 0 {
+		fmt.Printf("Rate limit: %d/%d requests remaining\n", remaining, limit)
+	}
+	if apiClient.cache != nil {
+		fmt.Printf("Cache: %d not modified, %d fetched\n", apiClient.cache.hits.Load(), apiClient.cache.misses.Load())
 	}
 
+	// Read install details from the Homebrew taps
+	fmt.Println("Reading Homebrew formulae...")
+	formulae := fetchHomebrewFormulae(config.HomebrewTaps)
+
 	// Transform filtered repos
 	projects := make([]Project, 0, len(reposWithMetrics))
 	for _, repo := range reposWithMetrics {
 		project := Project{
-			ID:          strings.ToLower(repo.Name),
-			Name:        repo.Name,
-			Description: repo.Description,
-			Language:    repo.Language,
-			Platforms:   getPlatforms(repo.GitHubRepo),
-			Stars:       repo.StargazersCount,
-			GitHubURL:   repo.HTMLURL,
-			Category:    categorizeProject(repo.GitHubRepo),
-			Highlights:  getHighlights(repo.GitHubRepo),
-			UpdatedAt:   repo.UpdatedAt,
-			CreatedAt:   repo.CreatedAt,
-			Topics:      repo.Topics,
-			CommitCount:  repo.CommitCount,
-			ReleaseCount: repo.ReleaseCount,
-			HomepageURL:  repo.HomepageURL,
+			ID:                repo.projectID(),
+			Name:              repo.Name,
+			Owner:             repo.owner(),
+			Description:       repo.Description,
+			Language:          repo.Language,
+			Languages:         repo.Languages,
+			Platforms:         repo.Platforms,
+			Architectures:     repo.Architectures,
+			Stars:             repo.StargazersCount,
+			Forks:             repo.ForksCount,
+			OpenIssues:        repo.OpenIssuesCount,
+			GitHubURL:         repo.HTMLURL,
+			Highlights:        rules.highlights(repo.GitHubRepo),
+			UpdatedAt:         repo.UpdatedAt,
+			CreatedAt:         repo.CreatedAt,
+			PushedAt:          repo.PushedAt,
+			Topics:            repo.Topics,
+			Archived:          repo.Archived,
+			License:           repo.licenseName(),
+			Health:            healthOf(repo),
+			CommitCount:       repo.CommitCount,
+			CommitCountSource: repo.CommitCountSource,
+			ReleaseCount:      repo.ReleaseCount,
+			LatestRelease:     repo.LatestRelease,
+			Downloads:         repo.Downloads,
+			HomepageURL:       repo.HomepageURL,
+		}
+		if repo.carried {
+			project.carried = true
+			previous.restore(&project)
+		}
+		project.Category, _ = rules.category(repo.GitHubRepo)
+		project.PinOrder = pinOrder(repo.fullName(), pinnedRepos)
+		project.Pinned = project.PinOrder > 0
+		if formula, ok := formulae[project.ID]; ok {
+			project.Homebrew = &formula
+			project.Highlights = withHighlight(project.Highlights, "Homebrew available")
+		}
+		if len(project.Platforms) == 0 {
+			project.Platforms = getPlatforms(repo.GitHubRepo)
+		}
+		if project.Architectures == nil {
+			project.Architectures = []string{}
+		}
+		overrides.apply(&project)
+		if project.Languages == nil {
+			project.Languages = []LanguageShare{}
+		}
+		if project.Language == "" && len(project.Languages) > 0 {
+			project.Language = project.Languages[0].Name
 		}
 		if project.Language == "" {
 			project.Language = "Unknown"
@@ -204,6 +367,10 @@ func FetchData() error {
 		projects = append(projects, project)
 	}
 
+	for _, name := range overrides.missing(projects) {
+		fmt.Printf("Warning: %s is force-included in %s but wasn't fetched\n", name, overridesPath)
+	}
+
 	// Sort projects
 	sort.Slice(projects, func(i, j int) bool {
 		// Sort by stars first, then by commit count, then by update date
@@ -218,19 +385,77 @@ func FetchData() error {
 		return ti.After(tj)
 	})
 
-	// Select featured projects (for homepage - prioritize stars)
-	featuredProjects := selectFeaturedProjects(projects, pinnedRepos)
+	// Derive growth from earlier runs and record today's metrics if asked to
+	if err := recordHistory(projects, opts.Record); err != nil {
+		fmt.Printf("Warning: failed to update history: %v\n", err)
+	}
+
+	// Install stats and docs from Go, Swift, Rust and npm registries, and
+	// how many others depend on each project, before dependents can rank them
+	fmt.Println("Looking up projects in package registries...")
+	addPackages(projects, config.Registries)
+	fmt.Println("Counting dependents...")
+	addDependents(projects, config.Registries)
+
+	// Select featured projects in the requested order
+	featuredProjects := selectFeaturedProjects(projects, overrides, opts.Sort)
+
+	// Pull richer content from the READMEs of the selected projects
+	fmt.Println("Reading READMEs of featured projects...")
+	addReadmes(featuredProjects)
+
+	// Merged pull requests to repos owned by someone else
+	contributions := []Contribution{}
+	if config.Contributions {
+		if hasGitHubToken {
+			fmt.Println("Fetching external contributions...")
+			fetched, err := fetchContributions(config.Organizations)
+			if err != nil {
+				fmt.Printf("Warning: failed to fetch contributions: %v\n", err)
+			} else {
+				contributions = fetched
+			}
+		} else {
+			fmt.Println("Note: external contributions require GitHub credentials and will be skipped")
+		}
+	}
+
+	// The heatmap keeps its previous data when the calendar can't be read
+	if hasGitHubToken {
+		fmt.Println("Fetching contribution calendar...")
+		if err := writeContributionCalendar(); err != nil {
+			fmt.Printf("Warning: failed to fetch contribution calendar: %v\n", err)
+		}
+	} else {
+		fmt.Println("Note: the contribution calendar requires GitHub credentials and will be skipped")
+	}
+
+	// Keep every released project, with the READMEs just read, for the next
+	// incremental run
+	lastUpdated := time.Now().Format(time.RFC3339)
+	readmes := make(map[string]*ReadmeInfo, len(featuredProjects))
+	for _, p := range featuredProjects {
+		readmes[p.ID] = p.Readme
+	}
+	for i := range projects {
+		if readme, ok := readmes[projects[i].ID]; ok {
+			projects[i].Readme = readme
+		}
+	}
+	if err := writeSnapshot(snapshotPath, lastUpdated, projects); err != nil {
+		fmt.Printf("Warning: failed to write %s: %v\n", snapshotPath, err)
+	}
 
 	// Prepare output data
 	outputData := OpenSourceData{
-		LastUpdated: time.Now().Format(time.RFC3339),
-		TotalRepos:  len(repos),
-		Projects:    featuredProjects,
+		LastUpdated:   lastUpdated,
+		TotalRepos:    totalRepos,
+		Languages:     aggregateLanguages(featuredProjects),
+		Projects:      featuredProjects,
+		Contributions: contributions,
 	}
 
 	// Write to file
-	outputPath := filepath.Join("src", "data", "opensource.json")
-	
 	// Ensure directory exists
 	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
@@ -260,18 +485,15 @@ func FetchData() error {
 		categoryCounts[p.Category]++
 		totalCommits += p.CommitCount
 		totalStars += p.Stars
-		
-		// Check if pinned
-		for _, pinned := range pinnedRepos {
-			if p.ID == pinned {
-				pinnedCount++
-				break
-			}
+		if p.Pinned {
+			pinnedCount++
 		}
 	}
 
 	fmt.Println("\nProject stats:")
 	fmt.Printf("  Total projects: %d\n", len(outputData.Projects))
+	fmt.Printf("  Pinned projects: %d\n", pinnedCount)
+	fmt.Printf("  External contributions: %d repos\n", len(outputData.Contributions))
 	fmt.Printf("  Total stars: %d\n", totalStars)
 	fmt.Printf("  Total commits: %d\n", totalCommits)
 	fmt.Printf("  Average stars per project: %.1f\n", float64(totalStars)/float64(len(outputData.Projects)))
@@ -283,272 +505,244 @@ func FetchData() error {
 		fmt.Printf("  %d. %s - %d stars, %d commits\n", i+1, p.Name, p.Stars, p.CommitCount)
 	}
 	
+	fmt.Println("\nTop languages:")
+	for i := 0; i < 5 && i < len(outputData.Languages); i++ {
+		l := outputData.Languages[i]
+		fmt.Printf("  %d. %s - %.1f%%\n", i+1, l.Name, l.Percent)
+	}
+	
 	fmt.Println("\n✓ GitHub data updated successfully")
 
 	return nil
 }
 
-func fetchGitHubRepos() ([]GitHubRepo, error) {
-	url := fmt.Sprintf(githubAPIURL, githubUsername)
-	
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("User-Agent", "guitaripod-website")
-	
-	// Add token if available
-	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
-		req.Header.Set("Authorization", "token "+token)
+// isCandidate reports whether a repo is eligible before any metrics are known.
+// Archived repos are read-only, so they're left out unless asked for.
+func isCandidate(repo GitHubRepo, includeArchived bool) bool {
+	// Skip if it's in the exclude list or doesn't meet basic criteria
+	return !repo.Fork && !repo.Private && !contains(excludeRepos, repo.Name) &&
+		repo.Description != "" && (includeArchived || !repo.Archived)
+}
+
+// filterReleased keeps candidate repos with at least one release. Pinned and
+// force-included repos are kept regardless, since both are explicit choices;
+// hidden repos and Homebrew taps are always dropped.
+func filterReleased(repos []repoMetrics, pinnedRepos []string, overrides Overrides, config Config) []repoMetrics {
+	var released []repoMetrics
+	for _, repo := range repos {
+		if overrides.hidden(repo.projectID()) || config.isTap(repo.GitHubRepo) {
+			continue
+		}
+		if overrides.included(repo.projectID()) && !repo.Private {
+			fmt.Printf("  ✓ %s: %d commits, %d releases, %d stars (included by override)\n",
+				repo.Name, repo.CommitCount, repo.ReleaseCount, repo.StargazersCount)
+			released = append(released, repo)
+			continue
+		}
+		if !isCandidate(repo.GitHubRepo, true) {
+			continue
+		}
+		if repo.Archived && !config.IncludeArchived {
+			fmt.Printf("  ✗ %s: archived\n", repo.Name)
+			continue
+		}
+		if pinOrder(repo.fullName(), pinnedRepos) > 0 {
+			fmt.Printf("  📌 %s: pinned, %d commits, %d releases, %d stars\n",
+				repo.Name, repo.CommitCount, repo.ReleaseCount, repo.StargazersCount)
+			released = append(released, repo)
+			continue
+		}
+		if repo.ReleaseCount < 1 {
+			fmt.Printf("  ✗ %s: no releases\n", repo.Name)
+			continue
+		}
+		fmt.Printf("  ✓ %s: %d commits, %d releases, %d stars (released project)\n",
+			repo.Name, repo.CommitCount, repo.ReleaseCount, repo.StargazersCount)
+		released = append(released, repo)
 	}
+	return released
+}
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
+func workerCount() int {
+	if !hasGitHubToken {
+		return 2 // Stay gentle on the unauthenticated quota
 	}
-	defer resp.Body.Close()
+	return fetchWorkers
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API responded with %d", resp.StatusCode)
+// parallel calls fn for every index in [0, n) using a bounded pool of workers.
+func parallel(n int, fn func(i int)) {
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workerCount(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	for i := 0; i < n; i++ {
+		jobs <- i
 	}
+	close(jobs)
+	wg.Wait()
+}
 
-	var repos []GitHubRepo
-	if err := json.Unmarshal(body, &repos); err != nil {
-		return nil, err
+// collectMetrics fetches release and commit counts for each repo in parallel,
+// keeping only repos that have been released. Results keep the order of the
+// input.
+func collectMetrics(repos []GitHubRepo, overrides Overrides) []repoMetrics {
+	results := make([]*repoMetrics, len(repos))
+	parallel(len(repos), func(i int) {
+		results[i] = fetchRepoMetrics(repos[i], overrides.included(repos[i].projectID()))
+	})
+
+	var reposWithMetrics []repoMetrics
+	for _, result := range results {
+		if result != nil {
+			reposWithMetrics = append(reposWithMetrics, *result)
+		}
 	}
+	return reposWithMetrics
+}
 
-	return repos, nil
+// addDownloads sums release asset downloads for repos whose other release
+// details are already known. GraphQL can't total them without paging every
+// release's assets.
+func addDownloads(repos []repoMetrics) {
+	parallel(len(repos), func(i int) {
+		releases, err := getReleases(repos[i].GitHubRepo)
+		if err != nil {
+			fmt.Printf("  ⚠️  %s: couldn't fetch download counts: %v\n", repos[i].Name, err)
+			return
+		}
+		repos[i].Downloads = releases.Downloads
+		repos[i].Assets = releases.Assets
+	})
 }
 
-func getCommitCount(repo GitHubRepo) (int, error) {
-	// Try contributors endpoint first
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/contributors", githubUsername, repo.Name)
-	
-	req, err := http.NewRequest("GET", url, nil)
+// addReadmes reads the README of each project, keeping the one carried
+// from the previous run where there is one.
+func addReadmes(projects []Project) {
+	parallel(len(projects), func(i int) {
+		if projects[i].carried && projects[i].Readme != nil {
+			return
+		}
+		readme, err := getReadme(projects[i].Owner + "/" + projects[i].Name)
+		if err != nil {
+			fmt.Printf("  ⚠️  %s: couldn't read README: %v\n", projects[i].Name, err)
+			return
+		}
+		projects[i].Readme = readme
+	})
+}
+
+// fetchRepoMetrics fetches releases, commits and languages for a repo. Repos
+// without releases are skipped unless force is set.
+func fetchRepoMetrics(repo GitHubRepo, force bool) *repoMetrics {
+	// Fetch releases
+	releases, err := getReleases(repo)
 	if err != nil {
-		return 0, err
+		fmt.Printf("  ✗ %s: error fetching releases: %v\n", repo.Name, err)
+		return nil
 	}
-	
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("User-Agent", "guitaripod-website")
-	
-	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
-		req.Header.Set("Authorization", "token "+token)
+
+	// Simple filtering: must have at least 1 release
+	if releases.Count < 1 && !force {
+		fmt.Printf("  ✗ %s: no releases\n", repo.Name)
+		return nil
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	// Now fetch commit count for metadata (not for filtering)
+	commitCount, commitCountSource, err := getCommitCount(repo)
 	if err != nil {
-		return 0, err
+		// Use 0 if we can't get commit count, but don't skip the repo
+		fmt.Printf("  ⚠️  %s: couldn't fetch commit count, using 0: %v\n", repo.Name, err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return 0, err
-		}
+	languages, err := getLanguages(repo)
+	if err != nil {
+		fmt.Printf("  ⚠️  %s: couldn't fetch languages: %v\n", repo.Name, err)
+	}
 
-		var contributors []Contributor
-		if err := json.Unmarshal(body, &contributors); err != nil {
-			return 0, err
-		}
+	fmt.Printf("  ✓ %s: %d commits (%s), %d releases, %d stars (released project)\n",
+		repo.Name, commitCount, commitCountSource, releases.Count, repo.StargazersCount)
+	return &repoMetrics{
+		GitHubRepo:        repo,
+		CommitCount:       commitCount,
+		CommitCountSource: commitCountSource,
+		ReleaseCount:      releases.Count,
+		LatestRelease:     releases.Latest,
+		Downloads:         releases.Downloads,
+		Languages:         languages,
+		Assets:            releases.Assets,
+	}
+}
 
-		for _, c := range contributors {
-			if c.Login == githubUsername {
-				return c.Contributions, nil
-			}
-		}
-	} else if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
-		// If rate limited, wait and retry once
-		fmt.Printf("    ⚠️  Rate limited on commits, waiting 60 seconds before retry...\n")
-		time.Sleep(60 * time.Second)
-		
-		// Retry the request
-		resp2, err := client.Do(req)
-		if err != nil {
-			return 0, err
-		}
-		defer resp2.Body.Close()
-		
-		if resp2.StatusCode == http.StatusOK {
-			body, err := io.ReadAll(resp2.Body)
-			if err != nil {
-				return 0, err
-			}
+// fetchGitHubRepos lists every repository of the user, following the Link
+// header until there is no next page.
+func fetchGitHubRepos() ([]GitHubRepo, error) {
+	return fetchAllRepoPages(fmt.Sprintf(githubAPIURL, apiBaseURL, githubUsername))
+}
 
-			var contributors []Contributor
-			if err := json.Unmarshal(body, &contributors); err != nil {
-				return 0, err
-			}
+// fetchOrganizationRepos lists the public repositories of an organization.
+func fetchOrganizationRepos(org string) ([]GitHubRepo, error) {
+	return fetchAllRepoPages(fmt.Sprintf(githubOrgURL, apiBaseURL, org))
+}
 
-			for _, c := range contributors {
-				if c.Login == githubUsername {
-					return c.Contributions, nil
-				}
-			}
+func fetchAllRepoPages(url string) ([]GitHubRepo, error) {
+	var repos []GitHubRepo
+
+	for url != "" {
+		page, next, err := fetchRepoPage(url)
+		if err != nil {
+			return nil, err
 		}
+		repos = append(repos, page...)
+		url = next
 	}
 
-	// Fallback: count commits (simplified - just use 100 as max)
-	return 100, nil // Simplified for now
+	return repos, nil
 }
 
-func getReleaseCount(repo GitHubRepo) (int, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases?per_page=100", githubUsername, repo.Name)
-	
+func fetchRepoPage(url string) ([]GitHubRepo, string, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return 0, err
+		return nil, "", err
 	}
 	
-	// Set headers
-	req.Header.Set("User-Agent", "compiledthoughts-static-site")
-	
-	// Add auth if available
-	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
-		req.Header.Set("Authorization", "token "+token)
-	}
-	
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("User-Agent", "guitaripod-website")
+
+	resp, err := apiClient.do(req)
 	if err != nil {
-		return 0, err
+		return nil, "", err
 	}
 	defer resp.Body.Close()
-	
+
 	if resp.StatusCode != http.StatusOK {
-		// Return 0 releases for 404 (no releases) instead of error
-		if resp.StatusCode == http.StatusNotFound {
-			return 0, nil
-		}
-		// If rate limited, wait and retry once
-		if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
-			fmt.Printf("    ⚠️  Rate limited, waiting 60 seconds before retry...\n")
-			time.Sleep(60 * time.Second)
-			
-			// Retry the request
-			resp2, err := client.Do(req)
-			if err != nil {
-				return 0, err
-			}
-			defer resp2.Body.Close()
-			
-			if resp2.StatusCode == http.StatusOK {
-				var releases []map[string]interface{}
-				if err := json.NewDecoder(resp2.Body).Decode(&releases); err != nil {
-					return 0, err
-				}
-				return len(releases), nil
-			}
-			return 0, fmt.Errorf("GitHub API error after retry: %s", resp2.Status)
-		}
-		return 0, fmt.Errorf("GitHub API error: %s", resp.Status)
-	}
-	
-	var releases []map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
-		return 0, err
+		return nil, "", fmt.Errorf("GitHub API responded with %d", resp.StatusCode)
 	}
-	
-	return len(releases), nil
-}
-
-func categorizeProject(repo GitHubRepo) string {
-	name := strings.ToLower(repo.Name)
-	desc := strings.ToLower(repo.Description)
-	
-	if strings.Contains(name, "-cli") || strings.Contains(desc, "cli") {
-		return "CLI Tools"
-	} else if repo.Language == "Swift" && (strings.Contains(name, "kit") || strings.Contains(desc, "package")) {
-		return "Swift Packages"
-	} else if strings.Contains(desc, "gtk") || strings.Contains(desc, "desktop") || strings.Contains(desc, "app") {
-		return "Desktop Apps"
-	} else if repo.Language == "Swift" {
-		return "Swift Projects"
-	} else if repo.Language == "Go" {
-		return "Go Projects"
-	}
-	return "Other Projects"
-}
 
-func getHighlights(repo GitHubRepo) []string {
-	var highlights []string
-	desc := strings.ToLower(repo.Description)
-	
-	// Check for homebrew
-	if contains(repo.Topics, "homebrew") || strings.Contains(desc, "homebrew") {
-		highlights = append(highlights, "Homebrew available")
-	}
-	
-	// Check for cross-platform
-	if strings.Contains(desc, "cross-platform") || strings.Contains(desc, "linux") || strings.Contains(desc, "macos") {
-		highlights = append(highlights, "Cross-platform")
-	}
-	
-	// Check for testing
-	if contains(repo.Topics, "testing") || strings.Contains(desc, "test") {
-		highlights = append(highlights, "Well-tested")
-	}
-	
-	// Check for AI/ML
-	if strings.Contains(desc, "ai") || strings.Contains(desc, "ml") || 
-	   strings.Contains(desc, "openai") || strings.Contains(desc, "dalle") {
-		highlights = append(highlights, "AI-powered")
-	}
-	
-	// Check for specific technologies
-	if strings.Contains(desc, "gtk") {
-		highlights = append(highlights, "GTK4")
-	}
-	if strings.Contains(desc, "async") {
-		highlights = append(highlights, "async/await")
-	}
-	if strings.Contains(desc, "vim") {
-		highlights = append(highlights, "Vim controls")
-	}
-	if strings.Contains(desc, "zero dependencies") {
-		highlights = append(highlights, "Zero dependencies")
-	}
-	if strings.Contains(desc, "batch") {
-		highlights = append(highlights, "Batch generation")
-	}
-	
-	// Language-specific
-	if repo.Language == "Swift" && strings.Contains(desc, "linux") {
-		highlights = append(highlights, "Cross-platform Swift")
-	}
-	
-	// Add topics as highlights
-	if len(repo.Topics) > 0 {
-		for i, topic := range repo.Topics {
-			if i >= 2 {
-				break
-			}
-			if !containsIgnoreCase(highlights, topic) {
-				highlights = append(highlights, strings.Title(topic))
-			}
-		}
-	}
-	
-	// Return max 3 highlights
-	if len(highlights) > 3 {
-		return highlights[:3]
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
 	}
-	if len(highlights) == 0 {
-		return []string{} // Ensure we always return a non-nil slice
+
+	var repos []GitHubRepo
+	if err := json.Unmarshal(body, &repos); err != nil {
+		return nil, "", err
 	}
-	return highlights
+
+	return repos, parseLinkHeader(resp.Header.Get("Link"))["next"], nil
 }
 
+// getPlatforms guesses platforms from the language and description, for repos
+// whose manifests and release assets say nothing.
 func getPlatforms(repo GitHubRepo) []string {
 	var platforms []string
 	desc := strings.ToLower(repo.Description)
@@ -583,98 +777,113 @@ func getPlatforms(repo GitHubRepo) []string {
 	return unique
 }
 
-func fetchPinnedRepos() ([]string, error) {
-	query := `{
-		user(login: "guitaripod") {
-			pinnedItems(first: 6, types: REPOSITORY) {
-				nodes {
-					... on Repository {
-						name
-					}
-				}
-			}
+// selectFeaturedProjects keeps pinned and force-included projects plus those
+// that pass the quality thresholds, ordered by the given strategy. Stars, then
+// commits, then recency break ties for every strategy.
+func selectFeaturedProjects(projects []Project, overrides Overrides, sortBy string) []Project {
+	var featured []Project
+	for _, p := range projects {
+		// Include pinned and force-included projects, projects with >= 25
+		// commits, or projects with > 1 star regardless of commit count
+		if p.Pinned || overrides.included(p.ID) || p.CommitCount >= 25 || p.Stars > 1 {
+			featured = append(featured, p)
 		}
-	}`
-
-	gqlQuery := GraphQLQuery{Query: query}
-	jsonData, err := json.Marshal(gqlQuery)
-	if err != nil {
-		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", graphQLURL, strings.NewReader(string(jsonData)))
-	if err != nil {
-		return nil, err
-	}
+	sort.SliceStable(featured, func(i, j int) bool {
+		a, b := featured[i], featured[j]
+		ta, _ := time.Parse(time.RFC3339, a.UpdatedAt)
+		tb, _ := time.Parse(time.RFC3339, b.UpdatedAt)
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "guitaripod-website")
-	
-	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
-		req.Header.Set("Authorization", "Bearer "+token)
-	}
+		// Pinned projects always lead, in the order shown on the profile
+		if a.Pinned != b.Pinned {
+			return a.Pinned
+		}
+		if a.Pinned && a.PinOrder != b.PinOrder {
+			return a.PinOrder < b.PinOrder
+		}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+		switch sortBy {
+		case SortTrending:
+			if a.TrendingScore != b.TrendingScore {
+				return a.TrendingScore > b.TrendingScore
+			}
+		case SortRecent:
+			if !ta.Equal(tb) {
+				return ta.After(tb)
+			}
+		case SortDependents:
+			if a.Dependents != b.Dependents {
+				return a.Dependents > b.Dependents
+			}
+		}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GraphQL query failed with status %d", resp.StatusCode)
-	}
+		if a.Stars != b.Stars {
+			return a.Stars > b.Stars
+		}
+		if a.CommitCount != b.CommitCount {
+			return a.CommitCount > b.CommitCount
+		}
+		return ta.After(tb)
+	})
 
-	body, err := io.ReadAll(resp.Body)
+	// Return all featured projects (let the page decide how many to show)
+	return featured
+}
+
+// previousPinned returns the pinned repos of the last written output as
+// owner/name, in pin order, or nothing if it can't be read.
+func previousPinned(path string) []string {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, err
+		return nil
+	}
+	var existing OpenSourceData
+	if err := json.Unmarshal(data, &existing); err != nil {
+		return nil
 	}
 
-	var pinnedResp PinnedReposResponse
-	if err := json.Unmarshal(body, &pinnedResp); err != nil {
-		return nil, err
+	var pinned []Project
+	for _, p := range existing.Projects {
+		if p.Pinned {
+			pinned = append(pinned, p)
+		}
 	}
+	sort.Slice(pinned, func(i, j int) bool {
+		return pinned[i].PinOrder < pinned[j].PinOrder
+	})
 
-	var pinnedNames []string
-	for _, node := range pinnedResp.Data.User.PinnedItems.Nodes {
-		pinnedNames = append(pinnedNames, strings.ToLower(node.Name))
+	names := make([]string, len(pinned))
+	for i, p := range pinned {
+		names[i] = p.Owner + "/" + p.Name
 	}
+	return names
+}
 
-	return pinnedNames, nil
+// pinOrder returns the 1-based position of the repo, given as owner/name,
+// among the pinned repos, or 0 if it isn't pinned.
+func pinOrder(fullName string, pinnedRepos []string) int {
+	for i, pinned := range pinnedRepos {
+		if strings.EqualFold(pinned, fullName) {
+			return i + 1
+		}
+	}
+	return 0
 }
 
-func selectFeaturedProjects(projects []Project, pinnedRepos []string) []Project {
-	// Filter for quality projects only
-	var qualityProjects []Project
-	
-	for _, p := range projects {
-		// Include projects with >= 25 commits, or projects with > 1 star regardless of commit count
-		if p.CommitCount >= 25 || p.Stars > 1 {
-			qualityProjects = append(qualityProjects, p)
+// withHighlight puts a known fact ahead of the guessed highlights, keeping at
+// most three.
+func withHighlight(highlights []string, highlight string) []string {
+	result := []string{highlight}
+	for _, h := range highlights {
+		if !strings.EqualFold(h, highlight) {
+			result = append(result, h)
 		}
 	}
-	
-	// Sort by stars first (for homepage display)
-	sort.Slice(qualityProjects, func(i, j int) bool {
-		// Sort primarily by stars
-		if qualityProjects[i].Stars != qualityProjects[j].Stars {
-			return qualityProjects[i].Stars > qualityProjects[j].Stars
-		}
-		
-		// If stars are equal, sort by commits
-		if qualityProjects[i].CommitCount != qualityProjects[j].CommitCount {
-			return qualityProjects[i].CommitCount > qualityProjects[j].CommitCount
-		}
-		
-		// If both are equal, prefer more recent updates
-		ti, _ := time.Parse(time.RFC3339, qualityProjects[i].UpdatedAt)
-		tj, _ := time.Parse(time.RFC3339, qualityProjects[j].UpdatedAt)
-		return ti.After(tj)
-	})
-	
-	// Return all quality projects (let the page decide how many to show)
-	return qualityProjects
+	if len(result) > 3 {
+		return result[:3]
+	}
+	return result
 }
 
 // Helper functions
@@ -0,0 +1,72 @@
+package github
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestFetchAllRepoPages(t *testing.T) {
+	routes := make(map[string]response)
+	srv := fakeServer(t, routes)
+	routes["/users/guitaripod/repos?per_page=100&sort=updated"] = response{
+		Header: map[string]string{"Link": `<` + srv.URL + `/users/guitaripod/repos?per_page=100&sort=updated&page=2>; rel="next", ` +
+			`<` + srv.URL + `/users/guitaripod/repos?per_page=100&sort=updated&page=2>; rel="last"`},
+		Body: `[{"name":"ct"},{"name":"nasa-rs"}]`,
+	}
+	routes["/users/guitaripod/repos?per_page=100&sort=updated&page=2"] = response{
+		Header: map[string]string{"Link": `<` + srv.URL + `/users/guitaripod/repos?per_page=100&sort=updated&page=1>; rel="prev"`},
+		Body:   `[{"name":"GeminiKit"}]`,
+	}
+
+	repos, err := fetchGitHubRepos()
+	if err != nil {
+		t.Fatalf("fetchGitHubRepos: %v", err)
+	}
+	var names []string
+	for _, repo := range repos {
+		names = append(names, repo.Name)
+	}
+	if want := []string{"ct", "nasa-rs", "GeminiKit"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("repos = %v, want %v", names, want)
+	}
+
+	routes["/users/guitaripod/repos?per_page=100&sort=updated&page=2"] = response{Status: http.StatusBadGateway}
+	if _, err := fetchGitHubRepos(); err == nil {
+		t.Error("fetchGitHubRepos returned no error for a failed page")
+	}
+}
+
+func TestSelectFeaturedProjects(t *testing.T) {
+	projects := []Project{
+		{ID: "popular", Stars: 40, CommitCount: 90, UpdatedAt: "2025-01-01T00:00:00Z", TrendingScore: 1},
+		{ID: "second-pin", Pinned: true, PinOrder: 2},
+		{ID: "trending", Stars: 5, CommitCount: 30, UpdatedAt: "2025-02-01T00:00:00Z", TrendingScore: 9},
+		{ID: "tiny", Stars: 0, CommitCount: 3},
+		{ID: "included", CommitCount: 2, UpdatedAt: "2025-03-01T00:00:00Z"},
+		{ID: "first-pin", Pinned: true, PinOrder: 1, Stars: 1},
+	}
+	overrides := Overrides{"included": {Include: true}}
+
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{SortPinned, []string{"first-pin", "second-pin", "popular", "trending", "included"}},
+		{SortStars, []string{"first-pin", "second-pin", "popular", "trending", "included"}},
+		{SortTrending, []string{"first-pin", "second-pin", "trending", "popular", "included"}},
+		{SortRecent, []string{"first-pin", "second-pin", "included", "trending", "popular"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			var ids []string
+			for _, p := range selectFeaturedProjects(projects, overrides, tt.sort) {
+				ids = append(ids, p.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("featured = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package github
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// githubApp authenticates as a GitHub App installation. It is configured
+// through GITHUB_APP_ID, GITHUB_APP_PRIVATE_KEY or GITHUB_APP_PRIVATE_KEY_FILE
+// and optionally GITHUB_APP_INSTALLATION_ID; without it the installation on
+// the user's account is looked up.
+type githubApp struct {
+	id             string
+	installationID string
+	key            *rsa.PrivateKey
+}
+
+func loadGitHubApp() (*githubApp, error) {
+	id := os.Getenv("GITHUB_APP_ID")
+	if id == "" {
+		return nil, nil
+	}
+
+	keyPEM := []byte(os.Getenv("GITHUB_APP_PRIVATE_KEY"))
+	if path := os.Getenv("GITHUB_APP_PRIVATE_KEY_FILE"); len(keyPEM) == 0 && path != "" {
+		var err error
+		if keyPEM, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read GitHub App private key: %w", err)
+		}
+	}
+	if len(keyPEM) == 0 {
+		return nil, fmt.Errorf("GITHUB_APP_ID is set but neither GITHUB_APP_PRIVATE_KEY nor GITHUB_APP_PRIVATE_KEY_FILE is")
+	}
+
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub App private key: %w", err)
+	}
+
+	return &githubApp{
+		id:             id,
+		installationID: os.Getenv("GITHUB_APP_INSTALLATION_ID"),
+		key:            key,
+	}, nil
+}
+
+// parsePrivateKey accepts the PKCS#1 keys GitHub generates as well as PKCS#8.
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("expected an RSA key")
+	}
+	return key, nil
+}
+
+// jwt signs the short-lived RS256 token that authenticates as the app itself.
+func (a *githubApp) jwt() (string, error) {
+	now := time.Now()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		// Backdated to allow for clock drift, and GitHub caps the lifetime at 10 minutes
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": a.id,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// installationToken exchanges the app JWT for an installation access token.
+func (a *githubApp) installationToken() (string, time.Time, error) {
+	jwt, err := a.jwt()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign JWT: %w", err)
+	}
+
+	if a.installationID == "" {
+		var installation struct {
+			ID int64 `json:"id"`
+		}
+		url := fmt.Sprintf("%s/users/%s/installation", apiBaseURL, githubUsername)
+		if err := appRequest("GET", url, jwt, http.StatusOK, &installation); err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to find installation for %s: %w", githubUsername, err)
+		}
+		a.installationID = fmt.Sprint(installation.ID)
+	}
+
+	var token struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	url := fmt.Sprintf("%s/app/installations/%s/access_tokens", apiBaseURL, a.installationID)
+	if err := appRequest("POST", url, jwt, http.StatusCreated, &token); err != nil {
+		return "", time.Time{}, err
+	}
+	return token.Token, token.ExpiresAt, nil
+}
+
+// appRequest calls an endpoint authenticated as the app. The JWT header is
+// set up front so the client doesn't substitute the installation token.
+func appRequest(method, url, jwt string, want int, out interface{}) error {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("User-Agent", "guitaripod-website")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+
+	resp, err := apiClient.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != want {
+		return fmt.Errorf("GitHub API responded with %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
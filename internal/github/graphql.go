@@ -0,0 +1,415 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// repositoryFields selects everything needed to build a project, so a single
+// query covers what would otherwise take several REST calls per repo.
+const repositoryFields = `fragment repositoryFields on Repository {
+	name
+	nameWithOwner
+	description
+	primaryLanguage {
+		name
+	}
+	languages(first: 10, orderBy: {field: SIZE, direction: DESC}) {
+		edges {
+			size
+			node {
+				name
+			}
+		}
+	}
+	isFork
+	isPrivate
+	isArchived
+	stargazerCount
+	forkCount
+	url
+	homepageUrl
+	updatedAt
+	createdAt
+	pushedAt
+	licenseInfo {
+		spdxId
+		name
+	}
+	issues(states: OPEN) {
+		totalCount
+	}
+	pullRequests(states: OPEN) {
+		totalCount
+	}
+	repositoryTopics(first: 20) {
+		nodes {
+			topic {
+				name
+			}
+		}
+	}
+	releases(first: 20, orderBy: {field: CREATED_AT, direction: DESC}) {
+		totalCount
+		nodes {
+			tagName
+			name
+			publishedAt
+			isPrerelease
+			isDraft
+		}
+	}
+	defaultBranchRef {
+		name
+		target {
+			... on Commit {
+				history {
+					totalCount
+				}
+				statusCheckRollup {
+					state
+				}
+			}
+		}
+	}
+}`
+
+const repositoriesQuery = `query($login: String!, $cursor: String) {
+	user(login: $login) {
+		pinnedItems(first: 6, types: REPOSITORY) {
+			nodes {
+				... on Repository {
+					nameWithOwner
+				}
+			}
+		}
+		repositories(first: 100, after: $cursor, ownerAffiliations: OWNER, privacy: PUBLIC, orderBy: {field: UPDATED_AT, direction: DESC}) {
+			totalCount
+			pageInfo {
+				hasNextPage
+				endCursor
+			}
+			nodes {
+				...repositoryFields
+			}
+		}
+	}
+}
+` + repositoryFields
+
+const organizationRepositoriesQuery = `query($login: String!, $cursor: String) {
+	organization(login: $login) {
+		repositories(first: 100, after: $cursor, privacy: PUBLIC, orderBy: {field: UPDATED_AT, direction: DESC}) {
+			totalCount
+			pageInfo {
+				hasNextPage
+				endCursor
+			}
+			nodes {
+				...repositoryFields
+			}
+		}
+	}
+}
+` + repositoryFields
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+type repositoryConnection struct {
+	TotalCount int `json:"totalCount"`
+	PageInfo   struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []graphQLRepository `json:"nodes"`
+}
+
+type repositoriesResponse struct {
+	User struct {
+		PinnedItems struct {
+			Nodes []struct {
+				NameWithOwner string `json:"nameWithOwner"`
+			} `json:"nodes"`
+		} `json:"pinnedItems"`
+		Repositories repositoryConnection `json:"repositories"`
+	} `json:"user"`
+}
+
+type organizationRepositoriesResponse struct {
+	Organization *struct {
+		Repositories repositoryConnection `json:"repositories"`
+	} `json:"organization"`
+}
+
+type graphQLRepository struct {
+	Name            string `json:"name"`
+	NameWithOwner   string `json:"nameWithOwner"`
+	Description     string `json:"description"`
+	PrimaryLanguage *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+	Languages struct {
+		Edges []struct {
+			Size int `json:"size"`
+			Node struct {
+				Name string `json:"name"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"languages"`
+	IsFork         bool   `json:"isFork"`
+	IsPrivate      bool   `json:"isPrivate"`
+	IsArchived     bool   `json:"isArchived"`
+	StargazerCount int    `json:"stargazerCount"`
+	ForkCount      int    `json:"forkCount"`
+	URL            string `json:"url"`
+	HomepageURL    string `json:"homepageUrl"`
+	UpdatedAt      string `json:"updatedAt"`
+	CreatedAt      string `json:"createdAt"`
+	PushedAt       string `json:"pushedAt"`
+	LicenseInfo    *struct {
+		SPDXID string `json:"spdxId"`
+		Name   string `json:"name"`
+	} `json:"licenseInfo"`
+	Issues struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"issues"`
+	PullRequests struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"pullRequests"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		} `json:"nodes"`
+	} `json:"repositoryTopics"`
+	Releases struct {
+		TotalCount int `json:"totalCount"`
+		Nodes      []struct {
+			TagName      string `json:"tagName"`
+			Name         string `json:"name"`
+			PublishedAt  string `json:"publishedAt"`
+			IsPrerelease bool   `json:"isPrerelease"`
+			IsDraft      bool   `json:"isDraft"`
+		} `json:"nodes"`
+	} `json:"releases"`
+	DefaultBranchRef *struct {
+		Name   string `json:"name"`
+		Target struct {
+			History struct {
+				TotalCount int `json:"totalCount"`
+			} `json:"history"`
+			StatusCheckRollup *struct {
+				State string `json:"state"`
+			} `json:"statusCheckRollup"`
+		} `json:"target"`
+	} `json:"defaultBranchRef"`
+}
+
+// fetchRepositoriesGraphQL pages through every repository owned by the user,
+// returning each with its release and commit totals, the pinned repos as
+// lowercase owner/name and the total repository count. GraphQL requires
+// credentials.
+func fetchRepositoriesGraphQL() ([]repoMetrics, []string, int, error) {
+	var (
+		repos       []repoMetrics
+		pinnedNames []string
+		totalCount  int
+		cursor      *string
+	)
+
+	for {
+		var data repositoriesResponse
+		variables := map[string]interface{}{
+			"login":  githubUsername,
+			"cursor": cursor,
+		}
+		if err := graphQL(repositoriesQuery, variables, &data); err != nil {
+			return nil, nil, 0, err
+		}
+
+		if cursor == nil {
+			for _, node := range data.User.PinnedItems.Nodes {
+				pinnedNames = append(pinnedNames, strings.ToLower(node.NameWithOwner))
+			}
+			totalCount = data.User.Repositories.TotalCount
+		}
+
+		for _, node := range data.User.Repositories.Nodes {
+			repos = append(repos, node.toMetrics())
+		}
+
+		pageInfo := data.User.Repositories.PageInfo
+		if !pageInfo.HasNextPage {
+			break
+		}
+		endCursor := pageInfo.EndCursor
+		cursor = &endCursor
+	}
+
+	return repos, pinnedNames, totalCount, nil
+}
+
+// fetchOrganizationRepositoriesGraphQL pages through the public repositories
+// of an organization.
+func fetchOrganizationRepositoriesGraphQL(org string) ([]repoMetrics, error) {
+	var (
+		repos  []repoMetrics
+		cursor *string
+	)
+
+	for {
+		var data organizationRepositoriesResponse
+		variables := map[string]interface{}{
+			"login":  org,
+			"cursor": cursor,
+		}
+		if err := graphQL(organizationRepositoriesQuery, variables, &data); err != nil {
+			return nil, err
+		}
+		if data.Organization == nil {
+			return nil, fmt.Errorf("organization %s not found", org)
+		}
+
+		for _, node := range data.Organization.Repositories.Nodes {
+			repos = append(repos, node.toMetrics())
+		}
+
+		pageInfo := data.Organization.Repositories.PageInfo
+		if !pageInfo.HasNextPage {
+			break
+		}
+		endCursor := pageInfo.EndCursor
+		cursor = &endCursor
+	}
+
+	return repos, nil
+}
+
+func (node graphQLRepository) toMetrics() repoMetrics {
+	repo := GitHubRepo{
+		Name:            node.Name,
+		FullName:        node.NameWithOwner,
+		Description:     node.Description,
+		Fork:            node.IsFork,
+		Private:         node.IsPrivate,
+		Archived:        node.IsArchived,
+		StargazersCount: node.StargazerCount,
+		ForksCount:      node.ForkCount,
+		// Counted together to match the REST open_issues_count
+		OpenIssuesCount: node.Issues.TotalCount + node.PullRequests.TotalCount,
+		HTMLURL:         node.URL,
+		UpdatedAt:       node.UpdatedAt,
+		CreatedAt:       node.CreatedAt,
+		PushedAt:        node.PushedAt,
+		HomepageURL:     node.HomepageURL,
+		Topics:          []string{},
+	}
+	if node.PrimaryLanguage != nil {
+		repo.Language = node.PrimaryLanguage.Name
+	}
+	if node.LicenseInfo != nil {
+		repo.License = &License{SPDXID: node.LicenseInfo.SPDXID, Name: node.LicenseInfo.Name}
+	}
+	for _, topic := range node.RepositoryTopics.Nodes {
+		repo.Topics = append(repo.Topics, topic.Topic.Name)
+	}
+
+	// Empty repositories have no default branch
+	commitCount, commitCountSource, ciStatus := 0, commitCountUnknown, ciNone
+	if branch := node.DefaultBranchRef; branch != nil {
+		repo.DefaultBranch = branch.Name
+		commitCount = branch.Target.History.TotalCount
+		commitCountSource = commitCountExact
+		if rollup := branch.Target.StatusCheckRollup; rollup != nil {
+			ciStatus = rollupStatus(rollup.State)
+		}
+	}
+
+	sizes := make(map[string]int, len(node.Languages.Edges))
+	for _, edge := range node.Languages.Edges {
+		sizes[edge.Node.Name] = edge.Size
+	}
+
+	// Drafts are only visible with push access and aren't public, so they're
+	// left out like on the REST path. They sort first by creation date, so the
+	// fetched nodes hold them all unless a repo has 20 or more.
+	releaseCount := node.Releases.TotalCount
+	var latest *Release
+	for _, r := range node.Releases.Nodes {
+		if r.IsDraft {
+			releaseCount--
+			continue
+		}
+		if latest == nil || r.PublishedAt > latest.PublishedAt {
+			latest = &Release{
+				Tag:         r.TagName,
+				Name:        r.Name,
+				PublishedAt: r.PublishedAt,
+				Prerelease:  r.IsPrerelease,
+			}
+		}
+	}
+
+	return repoMetrics{
+		GitHubRepo:        repo,
+		CommitCount:       commitCount,
+		CommitCountSource: commitCountSource,
+		ReleaseCount:      releaseCount,
+		LatestRelease:     latest,
+		Languages:         languageBreakdown(sizes),
+		CIStatus:          ciStatus,
+	}
+}
+
+// graphQL runs a query against the GitHub GraphQL API and decodes its data
+// into out.
+func graphQL(query string, variables map[string]interface{}, out interface{}) error {
+	jsonData, err := json.Marshal(GraphQLQuery{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", graphQLURL, bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "guitaripod-website")
+
+	resp, err := apiClient.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GraphQL query failed with status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphQLError  `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return err
+	}
+	if len(envelope.Errors) > 0 {
+		return fmt.Errorf("GraphQL error: %s", envelope.Errors[0].Message)
+	}
+
+	return json.Unmarshal(envelope.Data, out)
+}
@@ -0,0 +1,60 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestToMetricsSkipsDraftReleases(t *testing.T) {
+	tests := []struct {
+		name   string
+		nodes  string
+		total  int
+		count  int
+		latest *Release
+	}{
+		{
+			name: "draft newest by creation",
+			nodes: `[{"tagName":"v2.0.0","name":"Next","publishedAt":null,"isDraft":true},
+				{"tagName":"v1.1.0","name":"Patch","publishedAt":"2025-02-01T00:00:00Z"},
+				{"tagName":"v1.0.0","name":"First","publishedAt":"2025-01-01T00:00:00Z"}]`,
+			total:  3,
+			count:  2,
+			latest: &Release{Tag: "v1.1.0", Name: "Patch", PublishedAt: "2025-02-01T00:00:00Z"},
+		},
+		{
+			name: "latest by publish date, not creation",
+			nodes: `[{"tagName":"v1.0.1","publishedAt":"2025-01-10T00:00:00Z","isPrerelease":true},
+				{"tagName":"v1.1.0","publishedAt":"2025-03-01T00:00:00Z"}]`,
+			total:  2,
+			count:  2,
+			latest: &Release{Tag: "v1.1.0", PublishedAt: "2025-03-01T00:00:00Z"},
+		},
+		{
+			name:  "only drafts",
+			nodes: `[{"tagName":"v0.1.0","isDraft":true}]`,
+			total: 1,
+		},
+		{name: "no releases", nodes: `[]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var node graphQLRepository
+			data := fmt.Sprintf(`{"name":"ct","releases":{"totalCount":%d,"nodes":%s}}`, tt.total, tt.nodes)
+			if err := json.Unmarshal([]byte(data), &node); err != nil {
+				t.Fatal(err)
+			}
+
+			metrics := node.toMetrics()
+			if metrics.ReleaseCount != tt.count {
+				t.Errorf("ReleaseCount = %d, want %d", metrics.ReleaseCount, tt.count)
+			}
+			if !reflect.DeepEqual(metrics.LatestRelease, tt.latest) {
+				t.Errorf("LatestRelease = %+v, want %+v", metrics.LatestRelease, tt.latest)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// recentPushWindow is how long after its last push a repo still counts as
+// maintained.
+const recentPushWindow = 180 * 24 * time.Hour
+
+// Combined outcome of the checks on the default branch's head commit.
+// An empty status means it couldn't be determined.
+const (
+	ciSuccess = "success"
+	ciFailure = "failure"
+	ciPending = "pending"
+	ciNone    = "none" // no checks ran
+)
+
+type License struct {
+	SPDXID string `json:"spdx_id"`
+	Name   string `json:"name"`
+}
+
+// Health summarizes signals that a project is still looked after.
+type Health struct {
+	HasLicense     bool   `json:"hasLicense"`
+	RecentlyPushed bool   `json:"recentlyPushed"` // pushed within the last six months
+	CI             string `json:"ci,omitempty"`   // success, failure, pending or none
+}
+
+// licenseName prefers the SPDX identifier. GitHub reports NOASSERTION for
+// licenses it can't classify, in which case the name is all there is.
+func (r GitHubRepo) licenseName() string {
+	if r.License == nil {
+		return ""
+	}
+	if r.License.SPDXID != "" && r.License.SPDXID != "NOASSERTION" {
+		return r.License.SPDXID
+	}
+	return r.License.Name
+}
+
+func healthOf(repo repoMetrics) Health {
+	health := Health{
+		HasLicense: repo.licenseName() != "",
+		CI:         repo.CIStatus,
+	}
+	if pushed, err := time.Parse(time.RFC3339, repo.PushedAt); err == nil {
+		health.RecentlyPushed = time.Since(pushed) < recentPushWindow
+	}
+	return health
+}
+
+type checkRunsResponse struct {
+	TotalCount int `json:"total_count"`
+	CheckRuns  []struct {
+		Name       string `json:"name"`
+		Status     string `json:"status"`
+		Conclusion string `json:"conclusion"`
+	} `json:"check_runs"`
+}
+
+// getCIStatus reads the check runs on the head of the default branch.
+func getCIStatus(repo GitHubRepo) (string, error) {
+	if repo.DefaultBranch == "" {
+		return ciNone, nil
+	}
+
+	var data checkRunsResponse
+	checksURL := fmt.Sprintf("%s/repos/%s/commits/%s/check-runs?per_page=100",
+		apiBaseURL, repo.fullName(), url.PathEscape(repo.DefaultBranch))
+	if err := getJSON(checksURL, &data); err != nil {
+		return "", err
+	}
+
+	conclusions := make([]string, 0, len(data.CheckRuns))
+	for _, run := range data.CheckRuns {
+		if run.Status != "completed" {
+			conclusions = append(conclusions, ciPending)
+			continue
+		}
+		conclusions = append(conclusions, run.Conclusion)
+	}
+	return combineConclusions(conclusions), nil
+}
+
+// combineConclusions reduces check run conclusions to one status: any failure
+// wins, then anything still running. Neutral and skipped runs don't count
+// against a commit, so a commit with only those has no checks.
+func combineConclusions(conclusions []string) string {
+	status := ciNone
+	for _, conclusion := range conclusions {
+		switch conclusion {
+		case "failure", "timed_out", "cancelled", "action_required", "startup_failure":
+			return ciFailure
+		case ciPending:
+			status = ciPending
+		case "neutral", "skipped":
+		default:
+			if status == ciNone {
+				status = ciSuccess
+			}
+		}
+	}
+	return status
+}
+
+// rollupStatus maps the GraphQL statusCheckRollup state, which also covers
+// commit statuses, onto the same values.
+func rollupStatus(state string) string {
+	switch state {
+	case "SUCCESS":
+		return ciSuccess
+	case "FAILURE", "ERROR":
+		return ciFailure
+	case "PENDING", "EXPECTED":
+		return ciPending
+	case "":
+		return ciNone
+	}
+	return ""
+}
+
+// addCIStatus fills in the check run status for repos listed over REST.
+func addCIStatus(repos []repoMetrics) {
+	parallel(len(repos), func(i int) {
+		status, err := getCIStatus(repos[i].GitHubRepo)
+		if err != nil {
+			fmt.Printf("  ⚠️  %s: couldn't read check runs: %v\n", repos[i].Name, err)
+			return
+		}
+		repos[i].CIStatus = status
+	})
+}
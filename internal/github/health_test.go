@@ -0,0 +1,50 @@
+package github
+
+import "testing"
+
+func TestCombineConclusions(t *testing.T) {
+	tests := []struct {
+		name        string
+		conclusions []string
+		want        string
+	}{
+		{"no runs", nil, ciNone},
+		{"all passed", []string{"success", "success"}, ciSuccess},
+		{"failure wins", []string{"success", ciPending, "failure"}, ciFailure},
+		{"cancelled fails", []string{"success", "cancelled"}, ciFailure},
+		{"timed out fails", []string{"timed_out"}, ciFailure},
+		{"running", []string{"success", ciPending}, ciPending},
+		{"skipped doesn't count", []string{"skipped", "success", "neutral"}, ciSuccess},
+		{"skipped next to running", []string{"skipped", ciPending}, ciPending},
+		{"only skipped", []string{"skipped", "neutral"}, ciNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := combineConclusions(tt.conclusions); got != tt.want {
+				t.Errorf("combineConclusions(%q) = %q, want %q", tt.conclusions, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRollupStatus(t *testing.T) {
+	tests := []struct {
+		state string
+		want  string
+	}{
+		{"SUCCESS", ciSuccess},
+		{"FAILURE", ciFailure},
+		{"ERROR", ciFailure},
+		{"PENDING", ciPending},
+		{"EXPECTED", ciPending},
+		{"", ciNone},
+		{"SOMETHING_NEW", ""},
+	}
+
+	for _, tt := range tests {
+		if got := rollupStatus(tt.state); got != tt.want {
+			t.Errorf("rollupStatus(%q) = %q, want %q", tt.state, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package github
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	historyDateFormat = "2006-01-02"
+	trendWindow       = 30 * 24 * time.Hour
+)
+
+var historyPath = filepath.Join("src", "data", "github-history.jsonl")
+
+type repoSnapshot struct {
+	Stars    int `json:"stars"`
+	Forks    int `json:"forks"`
+	Commits  int `json:"commits"`
+	Releases int `json:"releases"`
+}
+
+// historyEntry is one line of the append-only history file: the metrics of
+// every released repo on a given date, keyed by project ID.
+type historyEntry struct {
+	Date  string                  `json:"date"`
+	Repos map[string]repoSnapshot `json:"repos"`
+}
+
+// loadHistory reads every entry in date order. When a date was recorded more
+// than once, the last line wins.
+func loadHistory(path string) ([]historyEntry, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	byDate := make(map[string]historyEntry)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var entry historyEntry
+		if err := json.Unmarshal([]byte(text), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse %s line %d: %w", path, line, err)
+		}
+		byDate[entry.Date] = entry
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	entries := make([]historyEntry, 0, len(byDate))
+	for _, entry := range byDate {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Date < entries[j].Date
+	})
+	return entries, nil
+}
+
+func appendHistory(path string, entry historyEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to append history: %w", err)
+	}
+	return nil
+}
+
+func snapshotProjects(projects []Project, now time.Time) historyEntry {
+	entry := historyEntry{
+		Date:  now.Format(historyDateFormat),
+		Repos: make(map[string]repoSnapshot, len(projects)),
+	}
+	for _, p := range projects {
+		entry.Repos[p.ID] = repoSnapshot{
+			Stars:    p.Stars,
+			Forks:    p.Forks,
+			Commits:  p.CommitCount,
+			Releases: p.ReleaseCount,
+		}
+	}
+	return entry
+}
+
+// trendBaseline picks the newest entry at least 30 days old, or the oldest
+// entry before today while the history is still shorter than that.
+func trendBaseline(history []historyEntry, now time.Time) *historyEntry {
+	today := now.Format(historyDateFormat)
+	cutoff := now.Add(-trendWindow).Format(historyDateFormat)
+
+	var baseline *historyEntry
+	for i := range history {
+		if history[i].Date >= today {
+			break
+		}
+		if baseline == nil || history[i].Date <= cutoff {
+			baseline = &history[i]
+		}
+	}
+	return baseline
+}
+
+// applyTrends fills in growth since the baseline and a trending score that
+// weighs new stars over new releases over new commits.
+func applyTrends(projects []Project, history []historyEntry, now time.Time) {
+	baseline := trendBaseline(history, now)
+	if baseline == nil {
+		return
+	}
+
+	for i := range projects {
+		p := &projects[i]
+		before, ok := baseline.Repos[p.ID]
+		if !ok {
+			continue
+		}
+
+		p.StarsGained30d = p.Stars - before.Stars
+		commitsGained := max(p.CommitCount-before.Commits, 0)
+		releasesGained := max(p.ReleaseCount-before.Releases, 0)
+
+		score := 3*float64(p.StarsGained30d) + 2*float64(releasesGained) + 0.2*float64(commitsGained)
+		p.TrendingScore = math.Round(score*10) / 10
+	}
+}
+
+// recordHistory derives trend fields from earlier runs. Today's metrics are
+// only appended to the history file when record is set, so regular builds
+// read the committed history without changing it.
+func recordHistory(projects []Project, record bool) error {
+	now := time.Now()
+
+	history, err := loadHistory(historyPath)
+	if err != nil {
+		return err
+	}
+	applyTrends(projects, history, now)
+
+	if !record {
+		return nil
+	}
+	if err := appendHistory(historyPath, snapshotProjects(projects, now)); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Recorded today's metrics in %s\n", historyPath)
+	return nil
+}
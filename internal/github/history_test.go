@@ -0,0 +1,115 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func entry(date string, repos map[string]repoSnapshot) historyEntry {
+	return historyEntry{Date: date, Repos: repos}
+}
+
+func TestTrendBaseline(t *testing.T) {
+	now := time.Date(2025, 4, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		dates   []string
+		want    string
+		wantNil bool
+	}{
+		{name: "newest entry at least 30 days old", dates: []string{"2025-02-01", "2025-03-10", "2025-03-16", "2025-04-01"}, want: "2025-03-16"},
+		{name: "exactly 30 days", dates: []string{"2025-03-01", "2025-03-16", "2025-03-17"}, want: "2025-03-16"},
+		{name: "oldest entry while history is short", dates: []string{"2025-04-01", "2025-04-10"}, want: "2025-04-01"},
+		{name: "today doesn't count", dates: []string{"2025-04-15"}, wantNil: true},
+		{name: "no history", wantNil: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var history []historyEntry
+			for _, date := range tt.dates {
+				history = append(history, entry(date, nil))
+			}
+
+			baseline := trendBaseline(history, now)
+			switch {
+			case tt.wantNil && baseline != nil:
+				t.Errorf("baseline = %s, want none", baseline.Date)
+			case !tt.wantNil && (baseline == nil || baseline.Date != tt.want):
+				t.Errorf("baseline = %v, want %s", baseline, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyTrends(t *testing.T) {
+	now := time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC)
+	history := []historyEntry{
+		entry("2025-03-01", map[string]repoSnapshot{
+			"ct":      {Stars: 10, Commits: 100, Releases: 2},
+			"nasa-rs": {Stars: 50, Commits: 80, Releases: 4},
+		}),
+	}
+	projects := []Project{
+		{ID: "ct", Stars: 14, CommitCount: 110, ReleaseCount: 3},
+		{ID: "nasa-rs", Stars: 48, CommitCount: 80, ReleaseCount: 4},
+		{ID: "new", Stars: 5, CommitCount: 3},
+	}
+
+	applyTrends(projects, history, now)
+
+	tests := []struct {
+		id     string
+		gained int
+		score  float64
+	}{
+		{"ct", 4, 3*4 + 2*1 + 0.2*10},
+		{"nasa-rs", -2, -6},
+		{"new", 0, 0},
+	}
+	for i, tt := range tests {
+		if p := projects[i]; p.StarsGained30d != tt.gained || p.TrendingScore != tt.score {
+			t.Errorf("%s: starsGained30d, trendingScore = %d, %v, want %d, %v", tt.id, p.StarsGained30d, p.TrendingScore, tt.gained, tt.score)
+		}
+	}
+}
+
+func TestRecordHistory(t *testing.T) {
+	path := historyPath
+	historyPath = filepath.Join(t.TempDir(), "github-history.jsonl")
+	t.Cleanup(func() { historyPath = path })
+
+	projects := []Project{{ID: "ct", Stars: 3}}
+	if err := recordHistory(projects, false); err != nil {
+		t.Fatalf("recordHistory: %v", err)
+	}
+	if _, err := os.Stat(historyPath); !os.IsNotExist(err) {
+		t.Fatal("history was written without record")
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := recordHistory(projects, true); err != nil {
+			t.Fatalf("recordHistory: %v", err)
+		}
+	}
+	data, err := os.ReadFile(historyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("history has %d lines, want 2", lines)
+	}
+
+	// Recording twice on one day keeps a single entry for it
+	history, err := loadHistory(historyPath)
+	if err != nil {
+		t.Fatalf("loadHistory: %v", err)
+	}
+	if len(history) != 1 || history[0].Repos["ct"].Stars != 3 {
+		t.Errorf("history = %+v, want one entry for today", history)
+	}
+}
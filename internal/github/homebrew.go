@@ -0,0 +1,149 @@
+package github
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	formulaVersionPattern  = regexp.MustCompile(`(?m)^\s*version\s+"([^"]+)"`)
+	formulaURLPattern      = regexp.MustCompile(`(?m)^\s*(?:url|homepage)\s+"([^"]+)"`)
+	formulaURLVersion      = regexp.MustCompile(`/(?:v|download/v?)(\d+(?:\.\d+)+)`)
+	formulaRepoPattern     = regexp.MustCompile(`github\.com/` + githubUsername + `/([A-Za-z0-9_.-]+)`)
+	formulaPlatformBlocks  = regexp.MustCompile(`(?m)^\s*(on_macos|on_linux)\b`)
+	formulaDependsPlatform = regexp.MustCompile(`(?m)^\s*depends_on\s+:(macos|linux)`)
+)
+
+// HomebrewFormula describes how to install a project from one of the taps.
+type HomebrewFormula struct {
+	Formula   string   `json:"formula"`
+	Tap       string   `json:"tap"`
+	Install   string   `json:"install"`
+	Version   string   `json:"version,omitempty"`
+	Platforms []string `json:"platforms"`
+}
+
+type contentEntry struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Type     string `json:"type"`
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
+// fetchHomebrewFormulae reads every formula in the taps, given as
+// owner/homebrew-name, and maps it to the lowercased name of the repo it builds.
+func fetchHomebrewFormulae(taps []string) map[string]HomebrewFormula {
+	formulae := make(map[string]HomebrewFormula)
+
+	for _, tapRepo := range taps {
+		owner, repoName, ok := strings.Cut(tapRepo, "/")
+		if !ok {
+			fmt.Printf("  ⚠️  %s: expected owner/homebrew-name\n", tapRepo)
+			continue
+		}
+
+		entries, err := getContents(tapRepo, "Formula")
+		if err != nil {
+			fmt.Printf("  ⚠️  %s: couldn't list formulae: %v\n", tapRepo, err)
+			continue
+		}
+
+		tap := owner + "/" + strings.TrimPrefix(repoName, "homebrew-")
+		for _, entry := range entries {
+			if entry.Type != "file" || !strings.HasSuffix(entry.Name, ".rb") {
+				continue
+			}
+
+			source, err := getFileContent(tapRepo, entry.Path)
+			if err != nil {
+				fmt.Printf("  ⚠️  %s: couldn't read %s: %v\n", tapRepo, entry.Path, err)
+				continue
+			}
+
+			name := strings.TrimSuffix(entry.Name, ".rb")
+			repoName, formula := parseFormula(name, tap, source)
+			formulae[strings.ToLower(repoName)] = formula
+			fmt.Printf("  🍺 %s: %s (%s)\n", repoName, formula.Install, formula.Version)
+		}
+	}
+
+	return formulae
+}
+
+// parseFormula extracts the version, platforms and source repo from a
+// formula's Ruby source. The repo falls back to the formula name.
+func parseFormula(name, tap, source string) (string, HomebrewFormula) {
+	formula := HomebrewFormula{
+		Formula:   name,
+		Tap:       tap,
+		Install:   fmt.Sprintf("brew install %s/%s", tap, name),
+		Platforms: []string{},
+	}
+
+	if match := formulaVersionPattern.FindStringSubmatch(source); match != nil {
+		formula.Version = match[1]
+	}
+
+	repoName := name
+	for _, match := range formulaURLPattern.FindAllStringSubmatch(source, -1) {
+		if formula.Version == "" {
+			if v := formulaURLVersion.FindStringSubmatch(match[1]); v != nil {
+				formula.Version = v[1]
+			}
+		}
+		if repo := formulaRepoPattern.FindStringSubmatch(match[1]); repo != nil && !strings.HasPrefix(repo[1], "homebrew-") {
+			repoName = strings.TrimSuffix(repo[1], ".git")
+		}
+	}
+
+	// Platform-specific blocks restrict the formula; without them it builds anywhere brew runs
+	seen := make(map[string]bool)
+	for _, match := range formulaPlatformBlocks.FindAllStringSubmatch(source, -1) {
+		seen[match[1]] = true
+	}
+	for _, match := range formulaDependsPlatform.FindAllStringSubmatch(source, -1) {
+		seen["on_"+match[1]] = true
+	}
+	if seen["on_macos"] || !seen["on_linux"] {
+		formula.Platforms = append(formula.Platforms, "macOS")
+	}
+	if seen["on_linux"] || !seen["on_macos"] {
+		formula.Platforms = append(formula.Platforms, "Linux")
+	}
+
+	return repoName, formula
+}
+
+// getContents lists a directory of an owner/name repo; an empty dir lists
+// the root.
+func getContents(fullName, dir string) ([]contentEntry, error) {
+	var entries []contentEntry
+	if err := getJSON(contentsURL(fullName, dir), &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func getFileContent(fullName, filePath string) (string, error) {
+	var entry contentEntry
+	if err := getJSON(contentsURL(fullName, filePath), &entry); err != nil {
+		return "", err
+	}
+	if entry.Encoding != "base64" {
+		return "", fmt.Errorf("unexpected encoding %q", entry.Encoding)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(entry.Content, "\n", ""))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func contentsURL(fullName, filePath string) string {
+	return fmt.Sprintf("%s/repos/%s/contents/%s", apiBaseURL, fullName, strings.TrimPrefix(path.Clean("/"+filePath), "/"))
+}
@@ -0,0 +1,76 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFormula(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		repo     string
+		version  string
+		platform []string
+	}{
+		{
+			name: "version from the download URL",
+			source: `class ApodCli < Formula
+  desc "Astronomy Picture of the Day"
+  homepage "https://github.com/guitaripod/apod-cli"
+  url "https://github.com/guitaripod/apod-cli/releases/download/v1.4.2/apod-cli.tar.gz"
+end`,
+			repo:     "apod-cli",
+			version:  "1.4.2",
+			platform: []string{"macOS", "Linux"},
+		},
+		{
+			name: "explicit version, macOS only",
+			source: `class Songlink < Formula
+  url "https://github.com/guitaripod/songlink-cli.git", tag: "v0.9.0"
+  version "0.9.1"
+  depends_on :macos
+end`,
+			repo:     "songlink-cli",
+			version:  "0.9.1",
+			platform: []string{"macOS"},
+		},
+		{
+			name: "platform blocks, tap homepage",
+			source: `class Tool < Formula
+  homepage "https://github.com/guitaripod/homebrew-tools"
+  on_linux do
+    url "https://example.com/tool-linux.tar.gz"
+  end
+end`,
+			repo:     "tool",
+			platform: []string{"Linux"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, formula := parseFormula(tt.repo, "guitaripod/tools", tt.source)
+			if repo != tt.repo {
+				t.Errorf("repo = %q, want %q", repo, tt.repo)
+			}
+			if formula.Version != tt.version {
+				t.Errorf("version = %q, want %q", formula.Version, tt.version)
+			}
+			if !reflect.DeepEqual(formula.Platforms, tt.platform) {
+				t.Errorf("platforms = %v, want %v", formula.Platforms, tt.platform)
+			}
+			if want := "brew install guitaripod/tools/" + tt.repo; formula.Install != want {
+				t.Errorf("install = %q, want %q", formula.Install, want)
+			}
+		})
+	}
+}
+
+func TestParseFormulaRepoDiffersFromName(t *testing.T) {
+	repo, _ := parseFormula("songlink", "guitaripod/songlink-cli",
+		`url "https://github.com/guitaripod/songlink-cli/archive/refs/tags/v1.0.0.tar.gz"`)
+	if repo != "songlink-cli" {
+		t.Errorf("repo = %q, want songlink-cli", repo)
+	}
+}
@@ -0,0 +1,151 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Every released project of the last run, not just the featured ones that
+// go into opensource.json, so incremental runs know what was filtered out
+var snapshotPath = filepath.Join(".cache", "ct", "released.json")
+
+// previousData is the last run's snapshot, indexed by project ID.
+type previousData struct {
+	lastUpdated time.Time
+	projects    map[string]Project
+}
+
+func loadPreviousData(path string) (*previousData, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var existing OpenSourceData
+	if err := json.Unmarshal(data, &existing); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	lastUpdated, err := time.Parse(time.RFC3339, existing.LastUpdated)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lastUpdated %q: %w", existing.LastUpdated, err)
+	}
+
+	previous := &previousData{
+		lastUpdated: lastUpdated,
+		projects:    make(map[string]Project, len(existing.Projects)),
+	}
+	for _, p := range existing.Projects {
+		previous.projects[p.ID] = p
+	}
+	return previous, nil
+}
+
+// writeSnapshot records every released project for the next incremental run.
+func writeSnapshot(path string, lastUpdated string, projects []Project) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	data, err := json.Marshal(OpenSourceData{LastUpdated: lastUpdated, Projects: projects})
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// unchanged reports whether neither pushed_at nor updated_at moved past the
+// previous run. Repos with unparseable timestamps count as changed.
+func (p *previousData) unchanged(repo GitHubRepo) bool {
+	for _, timestamp := range []string{repo.PushedAt, repo.UpdatedAt} {
+		t, err := time.Parse(time.RFC3339, timestamp)
+		if err != nil || t.After(p.lastUpdated) {
+			return false
+		}
+	}
+	return true
+}
+
+// split separates repos that need fresh metrics from those whose metrics can
+// be carried forward. Unchanged repos missing from the previous run were
+// filtered out last time and stay out, unless an override now includes them.
+func (p *previousData) split(repos []GitHubRepo, overrides Overrides) ([]GitHubRepo, []repoMetrics) {
+	var (
+		stale   []GitHubRepo
+		carried []repoMetrics
+	)
+
+	for _, repo := range repos {
+		if !p.unchanged(repo) {
+			stale = append(stale, repo)
+			continue
+		}
+
+		project, ok := p.projects[repo.projectID()]
+		if !ok && overrides.included(repo.projectID()) {
+			stale = append(stale, repo)
+			continue
+		}
+		if !ok {
+			fmt.Printf("  - %s: unchanged, previously filtered out\n", repo.Name)
+			continue
+		}
+
+		fmt.Printf("  ↺ %s: unchanged, reusing %d commits, %d releases\n",
+			repo.Name, project.CommitCount, project.ReleaseCount)
+		carried = append(carried, repoMetrics{
+			GitHubRepo:        repo,
+			CommitCount:       project.CommitCount,
+			CommitCountSource: project.CommitCountSource,
+			ReleaseCount:      project.ReleaseCount,
+			LatestRelease:     project.LatestRelease,
+			Downloads:         project.Downloads,
+			Languages:         project.Languages,
+			Platforms:         project.Platforms,
+			Architectures:     project.Architectures,
+			carried:           true,
+		})
+	}
+
+	return stale, carried
+}
+
+// carry is split for repos already filtered by GraphQL, whose counts come
+// fresh with the query. Only what takes extra requests per repo, downloads and
+// platforms, is carried forward.
+func (p *previousData) carry(repos []repoMetrics) ([]repoMetrics, []repoMetrics) {
+	var stale, carried []repoMetrics
+
+	for _, repo := range repos {
+		project, ok := p.projects[repo.projectID()]
+		if !ok || !p.unchanged(repo.GitHubRepo) {
+			stale = append(stale, repo)
+			continue
+		}
+
+		fmt.Printf("  ↺ %s: unchanged, reusing downloads and platforms\n", repo.Name)
+		repo.Downloads = project.Downloads
+		repo.Platforms = project.Platforms
+		repo.Architectures = project.Architectures
+		repo.carried = true
+		carried = append(carried, repo)
+	}
+
+	return stale, carried
+}
+
+// restore copies the registry, dependents and README details of a carried
+// project from the previous run.
+func (p *previousData) restore(project *Project) {
+	previous, ok := p.projects[project.ID]
+	if !ok {
+		return
+	}
+	project.Packages = previous.Packages
+	project.Dependents = previous.Dependents
+	project.DependentsUnknown = previous.DependentsUnknown
+	project.Readme = previous.Readme
+}
@@ -0,0 +1,140 @@
+package github
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// previousRun is a snapshot taken on 2025-03-01 holding ct and nasa-rs.
+func previousRun() *previousData {
+	return &previousData{
+		lastUpdated: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		projects: map[string]Project{
+			"ct": {
+				ID: "ct", CommitCount: 120, CommitCountSource: commitCountExact, ReleaseCount: 4,
+				Downloads: 900, Platforms: []string{"macOS", "Linux"}, Architectures: []string{"arm64"},
+				Packages: []Package{{Registry: "go", Name: "github.com/guitaripod/ct"}}, Dependents: 3,
+				Readme: &ReadmeInfo{Summary: "A CLI"},
+			},
+			"nasa-rs": {ID: "nasa-rs", CommitCount: 40, Downloads: 12},
+		},
+	}
+}
+
+func repoAt(name, pushedAt string) GitHubRepo {
+	return GitHubRepo{Name: name, FullName: "guitaripod/" + name, PushedAt: pushedAt, UpdatedAt: pushedAt}
+}
+
+func TestUnchanged(t *testing.T) {
+	previous := previousRun()
+	tests := map[string]struct {
+		repo GitHubRepo
+		want bool
+	}{
+		"before the last run": {repoAt("ct", "2025-02-01T00:00:00Z"), true},
+		"pushed since":        {repoAt("ct", "2025-03-02T00:00:00Z"), false},
+		"updated since":       {GitHubRepo{PushedAt: "2025-02-01T00:00:00Z", UpdatedAt: "2025-03-02T00:00:00Z"}, false},
+		"unparseable":         {repoAt("ct", "yesterday"), false},
+	}
+	for name, tt := range tests {
+		if got := previous.unchanged(tt.repo); got != tt.want {
+			t.Errorf("%s: unchanged = %v, want %v", name, got, tt.want)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	repos := []GitHubRepo{
+		repoAt("ct", "2025-02-01T00:00:00Z"),           // unchanged, carried
+		repoAt("nasa-rs", "2025-03-05T00:00:00Z"),      // pushed since
+		repoAt("filtered", "2025-02-01T00:00:00Z"),     // unchanged, filtered out last time
+		repoAt("now-included", "2025-02-01T00:00:00Z"), // unchanged, but an override includes it
+	}
+	overrides := Overrides{"now-included": {Include: true}}
+
+	stale, carried := previousRun().split(repos, overrides)
+
+	var staleNames []string
+	for _, repo := range stale {
+		staleNames = append(staleNames, repo.Name)
+	}
+	if want := []string{"nasa-rs", "now-included"}; !reflect.DeepEqual(staleNames, want) {
+		t.Errorf("stale = %v, want %v", staleNames, want)
+	}
+
+	if len(carried) != 1 {
+		t.Fatalf("carried %d repos, want 1", len(carried))
+	}
+	ct := carried[0]
+	if !ct.carried || ct.CommitCount != 120 || ct.ReleaseCount != 4 || ct.Downloads != 900 ||
+		!reflect.DeepEqual(ct.Platforms, []string{"macOS", "Linux"}) {
+		t.Errorf("carried ct = %+v, want the previous run's metrics", ct)
+	}
+}
+
+func TestCarry(t *testing.T) {
+	repos := []repoMetrics{
+		{GitHubRepo: repoAt("ct", "2025-02-01T00:00:00Z"), CommitCount: 125},
+		{GitHubRepo: repoAt("nasa-rs", "2025-03-05T00:00:00Z"), CommitCount: 41},
+		{GitHubRepo: repoAt("new", "2025-02-01T00:00:00Z"), CommitCount: 1},
+	}
+
+	stale, carried := previousRun().carry(repos)
+
+	if len(stale) != 2 || stale[0].Name != "nasa-rs" || stale[1].Name != "new" {
+		t.Errorf("stale = %+v, want nasa-rs and new", stale)
+	}
+	if len(carried) != 1 {
+		t.Fatalf("carried %d repos, want 1", len(carried))
+	}
+	// GraphQL's fresh counts stay; only what takes extra requests is reused
+	ct := carried[0]
+	if !ct.carried || ct.CommitCount != 125 || ct.Downloads != 900 || !reflect.DeepEqual(ct.Architectures, []string{"arm64"}) {
+		t.Errorf("carried ct = %+v", ct)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	previous := previousRun()
+
+	project := Project{ID: "ct", Packages: []Package{}}
+	previous.restore(&project)
+	if len(project.Packages) != 1 || project.Dependents != 3 || project.Readme == nil || project.Readme.Summary != "A CLI" {
+		t.Errorf("restored ct = %+v", project)
+	}
+
+	unknown := Project{ID: "unknown", Packages: []Package{}}
+	previous.restore(&unknown)
+	if unknown.Readme != nil || len(unknown.Packages) != 0 {
+		t.Errorf("restored unknown project = %+v, want it untouched", unknown)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "released.json")
+	projects := []Project{{ID: "ct", CommitCount: 120}, {ID: "filtered-by-selection", Stars: 0}}
+
+	if err := writeSnapshot(path, "2025-03-01T00:00:00Z", projects); err != nil {
+		t.Fatalf("writeSnapshot: %v", err)
+	}
+	previous, err := loadPreviousData(path)
+	if err != nil {
+		t.Fatalf("loadPreviousData: %v", err)
+	}
+
+	if !previous.lastUpdated.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("lastUpdated = %v", previous.lastUpdated)
+	}
+	if len(previous.projects) != 2 || previous.projects["ct"].CommitCount != 120 {
+		t.Errorf("projects = %+v, want both snapshot entries", previous.projects)
+	}
+
+	if err := writeSnapshot(path, "not a time", projects); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadPreviousData(path); err == nil {
+		t.Error("loadPreviousData accepted an invalid lastUpdated")
+	}
+}
@@ -0,0 +1,84 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+)
+
+// LanguageShare is one language's share of a codebase, weighted by bytes.
+type LanguageShare struct {
+	Name    string  `json:"name"`
+	Bytes   int     `json:"bytes"`
+	Percent float64 `json:"percent"`
+}
+
+// getLanguages returns the byte-weighted language breakdown of the repo.
+func getLanguages(repo GitHubRepo) ([]LanguageShare, error) {
+	url := fmt.Sprintf("%s/repos/%s/languages", apiBaseURL, repo.fullName())
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("User-Agent", "guitaripod-website")
+
+	resp, err := apiClient.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("languages endpoint responded with %d", resp.StatusCode)
+	}
+
+	var sizes map[string]int
+	if err := json.NewDecoder(resp.Body).Decode(&sizes); err != nil {
+		return nil, err
+	}
+
+	return languageBreakdown(sizes), nil
+}
+
+// languageBreakdown converts byte counts per language into shares sorted from
+// largest to smallest.
+func languageBreakdown(sizes map[string]int) []LanguageShare {
+	total := 0
+	for _, size := range sizes {
+		total += size
+	}
+
+	shares := make([]LanguageShare, 0, len(sizes))
+	for name, size := range sizes {
+		share := LanguageShare{Name: name, Bytes: size}
+		if total > 0 {
+			share.Percent = math.Round(float64(size)/float64(total)*1000) / 10
+		}
+		shares = append(shares, share)
+	}
+
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Bytes != shares[j].Bytes {
+			return shares[i].Bytes > shares[j].Bytes
+		}
+		return shares[i].Name < shares[j].Name
+	})
+	return shares
+}
+
+// aggregateLanguages sums the breakdowns of every project into a site-wide
+// distribution.
+func aggregateLanguages(projects []Project) []LanguageShare {
+	sizes := make(map[string]int)
+	for _, p := range projects {
+		for _, l := range p.Languages {
+			sizes[l.Name] += l.Bytes
+		}
+	}
+	return languageBreakdown(sizes)
+}
@@ -0,0 +1,79 @@
+package github
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestLanguageBreakdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		sizes map[string]int
+		want  []LanguageShare
+	}{
+		{
+			name:  "sorted by bytes, percent to one decimal",
+			sizes: map[string]int{"Shell": 100, "Go": 2900, "Makefile": 0},
+			want: []LanguageShare{
+				{Name: "Go", Bytes: 2900, Percent: 96.7},
+				{Name: "Shell", Bytes: 100, Percent: 3.3},
+				{Name: "Makefile", Bytes: 0, Percent: 0},
+			},
+		},
+		{
+			name:  "ties by name",
+			sizes: map[string]int{"Swift": 50, "C": 50},
+			want:  []LanguageShare{{Name: "C", Bytes: 50, Percent: 50}, {Name: "Swift", Bytes: 50, Percent: 50}},
+		},
+		{
+			name:  "no bytes",
+			sizes: map[string]int{"Go": 0},
+			want:  []LanguageShare{{Name: "Go"}},
+		},
+		{name: "no languages", sizes: nil, want: []LanguageShare{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := languageBreakdown(tt.sizes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("languageBreakdown = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateLanguages(t *testing.T) {
+	projects := []Project{
+		{Languages: []LanguageShare{{Name: "Go", Bytes: 600, Percent: 60}, {Name: "Shell", Bytes: 400, Percent: 40}}},
+		{Languages: []LanguageShare{{Name: "Swift", Bytes: 3000, Percent: 100}}},
+		{},
+	}
+	want := []LanguageShare{
+		{Name: "Swift", Bytes: 3000, Percent: 75},
+		{Name: "Go", Bytes: 600, Percent: 15},
+		{Name: "Shell", Bytes: 400, Percent: 10},
+	}
+	// Weighted by bytes, not by averaging each project's percentages
+	if got := aggregateLanguages(projects); !reflect.DeepEqual(got, want) {
+		t.Errorf("aggregateLanguages = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetLanguages(t *testing.T) {
+	fakeServer(t, map[string]response{
+		"/repos/guitaripod/ct/languages":     {Body: `{"Go":300,"Shell":100}`},
+		"/repos/guitaripod/broken/languages": {Status: http.StatusInternalServerError},
+	})
+
+	got, err := getLanguages(GitHubRepo{FullName: "guitaripod/ct"})
+	if err != nil {
+		t.Fatalf("getLanguages: %v", err)
+	}
+	if want := []LanguageShare{{Name: "Go", Bytes: 300, Percent: 75}, {Name: "Shell", Bytes: 100, Percent: 25}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("getLanguages = %+v, want %+v", got, want)
+	}
+	if _, err := getLanguages(GitHubRepo{FullName: "guitaripod/broken"}); err == nil {
+		t.Error("getLanguages returned no error for a 500 response")
+	}
+}
@@ -0,0 +1,96 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const overridesPath = "config/github-overrides.json"
+
+// Override adjusts how a single repository is selected and presented.
+// Include features the repo even when it has no releases or falls below the
+// quality thresholds; Hide drops it entirely. The remaining fields replace
+// the fetched or rule-derived values when set.
+type Override struct {
+	Include     bool     `json:"include,omitempty"`
+	Hide        bool     `json:"hide,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Category    string   `json:"category,omitempty"`
+	Highlights  []string `json:"highlights,omitempty"`
+	Platforms   []string `json:"platforms,omitempty"`
+}
+
+// Overrides maps project IDs to their override: the lowercase repo name for
+// the user's own repos and the lowercase owner/name for organization repos.
+type Overrides map[string]Override
+
+func loadOverrides(path string) (Overrides, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var raw map[string]Override
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	overrides := make(Overrides, len(raw))
+	for name, override := range raw {
+		if override.Include && override.Hide {
+			return nil, fmt.Errorf("invalid override for %s in %s: include and hide are mutually exclusive", name, path)
+		}
+		overrides[strings.ToLower(name)] = override
+	}
+	return overrides, nil
+}
+
+func (o Overrides) included(id string) bool {
+	return o[id].Include
+}
+
+func (o Overrides) hidden(id string) bool {
+	return o[id].Hide
+}
+
+// apply replaces the project's fields with any overridden values.
+func (o Overrides) apply(project *Project) {
+	override, ok := o[project.ID]
+	if !ok {
+		return
+	}
+
+	if override.Description != "" {
+		project.Description = override.Description
+	}
+	if override.Category != "" {
+		project.Category = override.Category
+	}
+	if override.Highlights != nil {
+		project.Highlights = override.Highlights
+	}
+	if override.Platforms != nil {
+		project.Platforms = override.Platforms
+	}
+}
+
+// missing returns the force-included repos that didn't make it into the
+// projects, usually because of a typo, a renamed repo or a fetch error.
+func (o Overrides) missing(projects []Project) []string {
+	found := make(map[string]bool, len(projects))
+	for _, p := range projects {
+		found[p.ID] = true
+	}
+
+	var names []string
+	for name, override := range o {
+		if override.Include && !found[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,309 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Manifests read for platform detection, matched case-sensitively against
+// the files in the repository root
+var (
+	swiftManifest      = "Package.swift"
+	goreleaserConfigs  = []string{".goreleaser.yml", ".goreleaser.yaml"}
+	cargoManifest      = "Cargo.toml"
+	swiftPlatformCall  = regexp.MustCompile(`\.(macOS|iOS|tvOS|watchOS|visionOS)\s*\(`)
+	rustTargetTriple   = regexp.MustCompile(`\b(?:x86_64|aarch64|i686|armv7|arm)-[a-z0-9_]+-[a-z0-9_]+(?:-[a-z0-9_]+)?\b`)
+	assetNameSeparator = regexp.MustCompile(`[-_. ]+`)
+)
+
+// Display order of platforms and architectures
+var (
+	platformOrder     = []string{"macOS", "iOS", "tvOS", "watchOS", "visionOS", "Linux", "Windows", "FreeBSD"}
+	architectureOrder = []string{"amd64", "arm64", "386", "arm"}
+)
+
+// Words in asset names and target triples that identify a platform
+var platformTokens = map[string]string{
+	"darwin":   "macOS",
+	"macos":    "macOS",
+	"mac":      "macOS",
+	"osx":      "macOS",
+	"dmg":      "macOS",
+	"pkg":      "macOS",
+	"linux":    "Linux",
+	"deb":      "Linux",
+	"rpm":      "Linux",
+	"appimage": "Linux",
+	"windows":  "Windows",
+	"win":      "Windows",
+	"win32":    "Windows",
+	"win64":    "Windows",
+	"msvc":     "Windows",
+	"exe":      "Windows",
+	"msi":      "Windows",
+	"freebsd":  "FreeBSD",
+	"ios":      "iOS",
+}
+
+// Words in asset names and target triples that identify an architecture.
+// universal is a fat macOS binary. x86_64 is rewritten to amd64 before
+// splitting, since the separators would break it apart.
+var architectureTokens = map[string][]string{
+	"amd64":     {"amd64"},
+	"x64":       {"amd64"},
+	"aarch64":   {"arm64"},
+	"arm64":     {"arm64"},
+	"i386":      {"386"},
+	"i686":      {"386"},
+	"386":       {"386"},
+	"x86":       {"386"},
+	"armv6":     {"arm"},
+	"armv7":     {"arm"},
+	"armv7l":    {"arm"},
+	"arm":       {"arm"},
+	"universal": {"amd64", "arm64"},
+}
+
+// GOOS and GOARCH values as they appear in .goreleaser.yml
+var (
+	goosPlatforms = map[string]string{
+		"darwin":  "macOS",
+		"linux":   "Linux",
+		"windows": "Windows",
+		"freebsd": "FreeBSD",
+		"ios":     "iOS",
+	}
+	goarchArchitectures = map[string]string{
+		"amd64": "amd64",
+		"arm64": "arm64",
+		"386":   "386",
+		"arm":   "arm",
+	}
+)
+
+// GoReleaser builds these when a build doesn't list goos or goarch
+var (
+	goreleaserDefaultGOOS   = []string{"darwin", "linux", "windows"}
+	goreleaserDefaultGOARCH = []string{"386", "amd64", "arm64"}
+)
+
+// platformSet collects platforms and architectures from several sources.
+type platformSet struct {
+	platforms     map[string]bool
+	architectures map[string]bool
+}
+
+func newPlatformSet() *platformSet {
+	return &platformSet{platforms: make(map[string]bool), architectures: make(map[string]bool)}
+}
+
+func (s *platformSet) addPlatform(name string) {
+	if name != "" {
+		s.platforms[name] = true
+	}
+}
+
+func (s *platformSet) addArchitecture(name string) {
+	if name != "" {
+		s.architectures[name] = true
+	}
+}
+
+func (s *platformSet) result() ([]string, []string) {
+	return ordered(s.platforms, platformOrder), ordered(s.architectures, architectureOrder)
+}
+
+// ordered returns the members of set in the given order, with anything
+// unknown sorted after them.
+func ordered(set map[string]bool, order []string) []string {
+	result := []string{}
+	for _, name := range order {
+		if set[name] {
+			result = append(result, name)
+		}
+	}
+
+	var rest []string
+	for name := range set {
+		if !contains(order, name) {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(result, rest...)
+}
+
+// detectPlatforms derives supported platforms and architectures from the
+// manifests in the repository root and the latest release's asset names.
+// Both are empty when nothing conclusive was found. A root that can't be
+// listed counts as having no manifests, so the assets are still read.
+func detectPlatforms(repo repoMetrics) ([]string, []string) {
+	set := newPlatformSet()
+
+	entries, err := getContents(repo.fullName(), "")
+	if err != nil {
+		fmt.Printf("  ⚠️  %s: couldn't list repository root: %v\n", repo.Name, err)
+	}
+	files := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if entry.Type == "file" {
+			files[entry.Name] = true
+		}
+	}
+
+	read := func(name string) string {
+		if !files[name] {
+			return ""
+		}
+		source, err := getFileContent(repo.fullName(), name)
+		if err != nil {
+			fmt.Printf("  ⚠️  %s: couldn't read %s: %v\n", repo.Name, name, err)
+			return ""
+		}
+		return source
+	}
+
+	if source := read(swiftManifest); source != "" {
+		parsePackageSwift(source, set)
+	}
+	for _, name := range goreleaserConfigs {
+		if source := read(name); source != "" {
+			parseGoreleaser(source, set)
+		}
+	}
+	if source := read(cargoManifest); source != "" {
+		parseCargoTargets(source, set)
+	}
+
+	// Carried-over repos have a latest release but no asset names yet
+	assets := repo.Assets
+	if assets == nil && repo.LatestRelease != nil {
+		if assets, err = getReleaseAssets(repo.GitHubRepo, repo.LatestRelease.Tag); err != nil {
+			fmt.Printf("  ⚠️  %s: couldn't list release assets: %v\n", repo.Name, err)
+		}
+	}
+	for _, asset := range assets {
+		parseAssetName(asset, set)
+	}
+
+	return set.result()
+}
+
+// parsePackageSwift reads the platforms a Swift package declares, such as
+// .macOS(.v13) or .iOS("16.0"). Packages that declare none are left to the
+// other sources.
+func parsePackageSwift(source string, set *platformSet) {
+	for _, match := range swiftPlatformCall.FindAllStringSubmatch(source, -1) {
+		set.addPlatform(match[1])
+	}
+}
+
+// parseGoreleaser reads the goos and goarch lists of every build, in either
+// block or flow style. Sections that refine existing targets rather than add
+// them, like ignore and format_overrides, are skipped.
+func parseGoreleaser(source string, set *platformSet) {
+	var (
+		goos, goarch []string
+		listKey      string
+		listIndent   = -1
+		skipIndent   = -1
+	)
+
+	add := func(key, value string) {
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch {
+		case value == "":
+		case key == "goos":
+			goos = append(goos, value)
+		default:
+			goarch = append(goarch, value)
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n") {
+		content, _, _ := strings.Cut(line, "#")
+		trimmed := strings.TrimSpace(content)
+		if trimmed == "" {
+			continue
+		}
+		indent := len(content) - len(strings.TrimLeft(content, " "))
+
+		if skipIndent >= 0 && indent > skipIndent {
+			continue
+		}
+		skipIndent = -1
+
+		if listKey != "" && indent >= listIndent && strings.HasPrefix(trimmed, "- ") {
+			add(listKey, strings.TrimPrefix(trimmed, "- "))
+			continue
+		}
+		listKey = ""
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(trimmed, "- "), ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		switch key {
+		case "ignore", "format_overrides", "overrides":
+			skipIndent = indent
+		case "goos", "goarch":
+			if value == "" {
+				listKey, listIndent = key, indent
+				continue
+			}
+			for _, item := range strings.Split(strings.Trim(value, "[]"), ",") {
+				add(key, item)
+			}
+		}
+	}
+
+	// Builds that don't list targets get GoReleaser's defaults
+	if len(goos) == 0 {
+		goos = goreleaserDefaultGOOS
+	}
+	if len(goarch) == 0 {
+		goarch = goreleaserDefaultGOARCH
+	}
+	for _, name := range goos {
+		set.addPlatform(goosPlatforms[name])
+	}
+	for _, name := range goarch {
+		set.addArchitecture(goarchArchitectures[name])
+	}
+}
+
+// parseCargoTargets reads Rust target triples, as listed by cargo-dist,
+// docs.rs metadata or [target.<triple>] tables.
+func parseCargoTargets(source string, set *platformSet) {
+	for _, triple := range rustTargetTriple.FindAllString(source, -1) {
+		parseAssetName(triple, set)
+	}
+}
+
+// parseAssetName reads the platform and architecture from a release asset
+// name such as tool_1.2.0_darwin_arm64.tar.gz or tool-x86_64-pc-windows-msvc.zip.
+func parseAssetName(name string, set *platformSet) {
+	name = strings.NewReplacer("x86_64", "amd64", "x86-64", "amd64").Replace(strings.ToLower(name))
+	for _, token := range assetNameSeparator.Split(name, -1) {
+		set.addPlatform(platformTokens[token])
+		for _, architecture := range architectureTokens[token] {
+			set.addArchitecture(architecture)
+		}
+	}
+}
+
+// addPlatforms detects platforms for every repo in parallel. Repos where
+// nothing was found keep the guess from getPlatforms, and repos carried from
+// the previous run keep what was detected then.
+func addPlatforms(repos []repoMetrics) {
+	parallel(len(repos), func(i int) {
+		if repos[i].carried {
+			return
+		}
+		repos[i].Platforms, repos[i].Architectures = detectPlatforms(repos[i])
+	})
+}
@@ -0,0 +1,153 @@
+package github
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// detect runs a parser on a fresh set and returns what it found.
+func detect(parse func(string, *platformSet), source string) ([]string, []string) {
+	set := newPlatformSet()
+	parse(source, set)
+	return set.result()
+}
+
+func TestParseGoreleaser(t *testing.T) {
+	tests := []struct {
+		name          string
+		source        string
+		platforms     []string
+		architectures []string
+	}{
+		{
+			name: "block lists",
+			source: `builds:
+  - id: ct
+    goos:
+      - linux
+      - darwin
+    goarch:
+      - amd64
+      - arm64
+`,
+			platforms:     []string{"macOS", "Linux"},
+			architectures: []string{"amd64", "arm64"},
+		},
+		{
+			name: "flow lists and comments",
+			source: `builds:
+  - goos: [linux, "windows"] # no macOS yet
+    goarch: ['amd64']
+`,
+			platforms:     []string{"Linux", "Windows"},
+			architectures: []string{"amd64"},
+		},
+		{
+			name: "ignore doesn't add targets",
+			source: `builds:
+  - goos:
+      - linux
+    goarch:
+      - amd64
+    ignore:
+      - goos: windows
+        goarch: arm64
+`,
+			platforms:     []string{"Linux"},
+			architectures: []string{"amd64"},
+		},
+		{
+			name:          "GoReleaser defaults",
+			source:        "builds:\n  - main: ./cmd/ct\n",
+			platforms:     []string{"macOS", "Linux", "Windows"},
+			architectures: []string{"amd64", "arm64", "386"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			platforms, architectures := detect(parseGoreleaser, tt.source)
+			if !reflect.DeepEqual(platforms, tt.platforms) || !reflect.DeepEqual(architectures, tt.architectures) {
+				t.Errorf("parseGoreleaser = %v %v, want %v %v", platforms, architectures, tt.platforms, tt.architectures)
+			}
+		})
+	}
+}
+
+func TestParsePackageSwift(t *testing.T) {
+	source := `let package = Package(
+    name: "GeminiKit",
+    platforms: [.macOS(.v13), .iOS("16.0"), .visionOS(.v1)],
+    products: [.library(name: "GeminiKit", targets: ["GeminiKit"])]
+)`
+	platforms, architectures := detect(parsePackageSwift, source)
+	if want := []string{"macOS", "iOS", "visionOS"}; !reflect.DeepEqual(platforms, want) {
+		t.Errorf("platforms = %v, want %v", platforms, want)
+	}
+	if len(architectures) != 0 {
+		t.Errorf("architectures = %v, want none", architectures)
+	}
+
+	if platforms, _ := detect(parsePackageSwift, `let package = Package(name: "Any")`); len(platforms) != 0 {
+		t.Errorf("platforms without a platforms list = %v, want none", platforms)
+	}
+}
+
+func TestParseCargoTargets(t *testing.T) {
+	source := `[workspace.metadata.dist]
+targets = ["aarch64-apple-darwin", "x86_64-unknown-linux-gnu", "x86_64-pc-windows-msvc"]
+
+[target.armv7-unknown-linux-gnueabihf]
+linker = "arm-linux-gnueabihf-gcc"
+`
+	platforms, architectures := detect(parseCargoTargets, source)
+	if want := []string{"macOS", "Linux", "Windows"}; !reflect.DeepEqual(platforms, want) {
+		t.Errorf("platforms = %v, want %v", platforms, want)
+	}
+	if want := []string{"amd64", "arm64", "arm"}; !reflect.DeepEqual(architectures, want) {
+		t.Errorf("architectures = %v, want %v", architectures, want)
+	}
+}
+
+func TestParseAssetName(t *testing.T) {
+	tests := []struct {
+		name          string
+		platforms     []string
+		architectures []string
+	}{
+		{"ct_1.2.0_darwin_arm64.tar.gz", []string{"macOS"}, []string{"arm64"}},
+		{"ct-x86_64-pc-windows-msvc.zip", []string{"Windows"}, []string{"amd64"}},
+		{"ct_1.2.0_Linux_armv7.deb", []string{"Linux"}, []string{"arm"}},
+		{"CT-universal.dmg", []string{"macOS"}, []string{"amd64", "arm64"}},
+		{"checksums.txt", []string{}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			platforms, architectures := detect(parseAssetName, tt.name)
+			if !reflect.DeepEqual(platforms, tt.platforms) || !reflect.DeepEqual(architectures, tt.architectures) {
+				t.Errorf("parseAssetName = %v %v, want %v %v", platforms, architectures, tt.platforms, tt.architectures)
+			}
+		})
+	}
+}
+
+func TestDetectPlatformsWithoutRootListing(t *testing.T) {
+	fakeServer(t, map[string]response{
+		"/repos/guitaripod/ct/contents/":        {Status: http.StatusInternalServerError, Body: `{}`},
+		"/repos/guitaripod/ct/releases/tags/v1": {Body: `{"assets":[{"name":"ct_linux_amd64.tar.gz"},{"name":"ct_darwin_arm64.tar.gz"}]}`},
+	})
+
+	repo := repoMetrics{
+		GitHubRepo:    GitHubRepo{Name: "ct", FullName: "guitaripod/ct"},
+		LatestRelease: &Release{Tag: "v1"},
+	}
+	platforms, architectures := detectPlatforms(repo)
+	if want := []string{"macOS", "Linux"}; !reflect.DeepEqual(platforms, want) {
+		t.Errorf("platforms = %v, want %v", platforms, want)
+	}
+	if want := []string{"amd64", "arm64"}; !reflect.DeepEqual(architectures, want) {
+		t.Errorf("architectures = %v, want %v", architectures, want)
+	}
+}
@@ -0,0 +1,221 @@
+package github
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	badgeLinkPattern  = regexp.MustCompile(`\[!\[([^\]]*)\]\(([^)\s]+)[^)]*\)\]\(([^)\s]+)[^)]*\)`)
+	markdownImage     = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)[^)]*\)`)
+	htmlImage         = regexp.MustCompile(`(?i)<img[^>]+src=["']([^"']+)["']`)
+	markdownLink      = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	htmlTag           = regexp.MustCompile(`<[^>]+>`)
+	emphasis          = regexp.MustCompile("[*_`]{1,3}([^*_`]+)[*_`]{1,3}")
+	installHeading    = regexp.MustCompile(`(?i)^#{1,6}\s.*\b(install|installation|getting started|setup)\b`)
+	installCommand    = regexp.MustCompile(`\b(brew|go|cargo|npm|pip|gem) install\b|swift package|\.package\(url:`)
+	badgeImageSubstrs = []string{"shields.io", "badge", "/actions/workflows/", "codecov.io", "goreportcard.com", "travis-ci"}
+)
+
+const (
+	maxSummaryLength = 400
+	maxBadges        = 8
+)
+
+// ReadmeInfo is the richer project content pulled from a repo's README.
+type ReadmeInfo struct {
+	Summary string  `json:"summary,omitempty"`
+	Install string  `json:"install,omitempty"`
+	Image   string  `json:"image,omitempty"`
+	Badges  []Badge `json:"badges"`
+}
+
+type Badge struct {
+	Alt   string `json:"alt"`
+	Image string `json:"image"`
+	Link  string `json:"link,omitempty"`
+}
+
+type readmeResponse struct {
+	Content     string `json:"content"`
+	Encoding    string `json:"encoding"`
+	DownloadURL string `json:"download_url"`
+}
+
+// getReadme downloads and parses the README of the repo, given as owner/name.
+func getReadme(repo string) (*ReadmeInfo, error) {
+	var readme readmeResponse
+	if err := getJSON(fmt.Sprintf("%s/repos/%s/readme", apiBaseURL, repo), &readme); err != nil {
+		return nil, err
+	}
+	if readme.Encoding != "base64" {
+		return nil, fmt.Errorf("unexpected encoding %q", readme.Encoding)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(readme.Content, "\n", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseReadme(string(data), readme.DownloadURL), nil
+}
+
+// parseReadme extracts the first prose paragraph, the install snippet, the
+// first non-badge image and the badges. Relative image paths are resolved
+// against baseURL, the README's raw download URL.
+func parseReadme(markdown, baseURL string) *ReadmeInfo {
+	info := &ReadmeInfo{Badges: []Badge{}}
+
+	var (
+		paragraph       []string
+		inCode          bool
+		codeLines       []string
+		afterInstall    bool
+		fallbackSnippet string
+	)
+
+	for _, line := range strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		// Fenced code blocks are candidates for the install snippet
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			if inCode {
+				snippet := strings.TrimSpace(strings.Join(codeLines, "\n"))
+				if info.Install == "" && afterInstall && snippet != "" {
+					info.Install = snippet
+				}
+				if fallbackSnippet == "" && installCommand.MatchString(snippet) {
+					fallbackSnippet = snippet
+				}
+				codeLines = nil
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			codeLines = append(codeLines, line)
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "#") {
+			afterInstall = installHeading.MatchString(trimmed)
+			if len(paragraph) > 0 && info.Summary == "" {
+				info.Summary = cleanParagraph(paragraph)
+			}
+			paragraph = nil
+			continue
+		}
+
+		collectImages(info, trimmed, baseURL)
+
+		if info.Summary != "" {
+			continue
+		}
+		if trimmed == "" {
+			if len(paragraph) > 0 {
+				info.Summary = cleanParagraph(paragraph)
+				paragraph = nil
+			}
+			continue
+		}
+		if isProse(trimmed) {
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+
+	if info.Summary == "" && len(paragraph) > 0 {
+		info.Summary = cleanParagraph(paragraph)
+	}
+	if info.Install == "" {
+		info.Install = fallbackSnippet
+	}
+	return info
+}
+
+func collectImages(info *ReadmeInfo, line, baseURL string) {
+	for _, match := range badgeLinkPattern.FindAllStringSubmatch(line, -1) {
+		if len(info.Badges) < maxBadges {
+			info.Badges = append(info.Badges, Badge{
+				Alt:   match[1],
+				Image: resolveURL(baseURL, match[2]),
+				Link:  resolveURL(baseURL, match[3]),
+			})
+		}
+	}
+	line = badgeLinkPattern.ReplaceAllString(line, "")
+
+	var sources []string
+	for _, match := range markdownImage.FindAllStringSubmatch(line, -1) {
+		sources = append(sources, match[2])
+	}
+	for _, match := range htmlImage.FindAllStringSubmatch(line, -1) {
+		sources = append(sources, match[1])
+	}
+
+	for _, src := range sources {
+		resolved := resolveURL(baseURL, src)
+		if isBadge(src) {
+			if len(info.Badges) < maxBadges {
+				info.Badges = append(info.Badges, Badge{Image: resolved})
+			}
+			continue
+		}
+		if info.Image == "" {
+			info.Image = resolved
+		}
+	}
+}
+
+// isProse reports whether a line is paragraph text rather than markup,
+// lists, quotes or tables.
+func isProse(line string) bool {
+	for _, prefix := range []string{"<", "![", "[![", "|", ">", "- ", "* ", "+ ", "---", "==="} {
+		if strings.HasPrefix(line, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+func cleanParagraph(lines []string) string {
+	text := strings.Join(lines, " ")
+	text = markdownImage.ReplaceAllString(text, "")
+	text = markdownLink.ReplaceAllString(text, "$1")
+	text = htmlTag.ReplaceAllString(text, "")
+	text = emphasis.ReplaceAllString(text, "$1")
+	text = strings.Join(strings.Fields(text), " ")
+
+	if len(text) > maxSummaryLength {
+		cut := strings.LastIndex(text[:maxSummaryLength], " ")
+		if cut <= 0 {
+			cut = maxSummaryLength
+		}
+		text = text[:cut] + "…"
+	}
+	return text
+}
+
+func isBadge(src string) bool {
+	lower := strings.ToLower(src)
+	for _, s := range badgeImageSubstrs {
+		if strings.Contains(lower, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func resolveURL(baseURL, ref string) string {
+	base, err := url.Parse(baseURL)
+	if err != nil || baseURL == "" {
+		return ref
+	}
+	resolved, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return resolved.String()
+}
@@ -0,0 +1,84 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseReadme(t *testing.T) {
+	const baseURL = "https://raw.githubusercontent.com/guitaripod/ct/main/README.md"
+
+	tests := []struct {
+		name     string
+		markdown string
+		want     *ReadmeInfo
+	}{
+		{
+			name: "badges, summary, image and install section",
+			markdown: `# ct
+
+[![CI](https://github.com/guitaripod/ct/actions/workflows/ci.yml/badge.svg)](https://github.com/guitaripod/ct/actions)
+![Go Report](https://goreportcard.com/badge/github.com/guitaripod/ct)
+
+A **fast** CLI for [compiled thoughts](https://compiledthoughts.pages.dev),
+written in ` + "`Go`" + `.
+
+![Screenshot](docs/screenshot.png)
+
+## Installation
+
+` + "```sh\nbrew install guitaripod/ct/ct\n```\n",
+			want: &ReadmeInfo{
+				Summary: "A fast CLI for compiled thoughts, written in Go.",
+				Install: "brew install guitaripod/ct/ct",
+				Image:   "https://raw.githubusercontent.com/guitaripod/ct/main/docs/screenshot.png",
+				Badges: []Badge{
+					{
+						Alt:   "CI",
+						Image: "https://github.com/guitaripod/ct/actions/workflows/ci.yml/badge.svg",
+						Link:  "https://github.com/guitaripod/ct/actions",
+					},
+					{Image: "https://goreportcard.com/badge/github.com/guitaripod/ct"},
+				},
+			},
+		},
+		{
+			name: "install command outside an install section",
+			markdown: `Rust bindings for the NASA APIs.
+
+` + "```\ncargo install nasa-rs\n```\n",
+			want: &ReadmeInfo{
+				Summary: "Rust bindings for the NASA APIs.",
+				Install: "cargo install nasa-rs",
+				Badges:  []Badge{},
+			},
+		},
+		{
+			name:     "markup only",
+			markdown: "<p align=\"center\"><img src=\"logo.svg\"></p>\n\n- a list\n> a quote\n",
+			want: &ReadmeInfo{
+				Image:  "https://raw.githubusercontent.com/guitaripod/ct/main/logo.svg",
+				Badges: []Badge{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseReadme(tt.markdown, baseURL); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseReadme = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanParagraphTruncates(t *testing.T) {
+	words := make([]string, 100)
+	for i := range words {
+		words[i] = "word"
+	}
+	got := cleanParagraph(words)
+	if len(got) > maxSummaryLength+len("…") || got[len(got)-len("…"):] != "…" {
+		t.Errorf("cleanParagraph = %q, want at most %d characters ending in an ellipsis", got, maxSummaryLength)
+	}
+}
@@ -0,0 +1,402 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Registry base URLs, replaced through the registries section of
+// config/github.json, e.g. to point at a local fake
+const (
+	defaultGoProxyURL           = "https://proxy.golang.org"
+	defaultPkgGoDevURL          = "https://pkg.go.dev"
+	defaultSwiftPackageIndexURL = "https://swiftpackageindex.com"
+	defaultCratesURL            = "https://crates.io"
+	defaultDocsRsURL            = "https://docs.rs"
+	defaultNPMRegistryURL       = "https://registry.npmjs.org"
+	defaultNPMDownloadsURL      = "https://api.npmjs.org"
+	defaultNPMWebURL            = "https://www.npmjs.com"
+	defaultDepsDevURL           = "https://api.deps.dev"
+)
+
+// RegistryConfig holds the base URL of each package registry. Empty fields
+// keep the public defaults.
+type RegistryConfig struct {
+	GoProxyURL           string `json:"goProxyURL,omitempty"`
+	PkgGoDevURL          string `json:"pkgGoDevURL,omitempty"`
+	SwiftPackageIndexURL string `json:"swiftPackageIndexURL,omitempty"`
+	CratesURL            string `json:"cratesURL,omitempty"`
+	DocsRsURL            string `json:"docsRsURL,omitempty"`
+	NPMRegistryURL       string `json:"npmRegistryURL,omitempty"`
+	NPMDownloadsURL      string `json:"npmDownloadsURL,omitempty"`
+	NPMWebURL            string `json:"npmWebURL,omitempty"`
+	DepsDevURL           string `json:"depsDevURL,omitempty"`
+}
+
+// withDefaults fills in the public registries and trims trailing slashes.
+func (c RegistryConfig) withDefaults() RegistryConfig {
+	for field, fallback := range map[*string]string{
+		&c.GoProxyURL:           defaultGoProxyURL,
+		&c.PkgGoDevURL:          defaultPkgGoDevURL,
+		&c.SwiftPackageIndexURL: defaultSwiftPackageIndexURL,
+		&c.CratesURL:            defaultCratesURL,
+		&c.DocsRsURL:            defaultDocsRsURL,
+		&c.NPMRegistryURL:       defaultNPMRegistryURL,
+		&c.NPMDownloadsURL:      defaultNPMDownloadsURL,
+		&c.NPMWebURL:            defaultNPMWebURL,
+		&c.DepsDevURL:           defaultDepsDevURL,
+	} {
+		if *field == "" {
+			*field = fallback
+		}
+		*field = strings.TrimSuffix(*field, "/")
+	}
+	return c
+}
+
+// Package is a project's listing in a package registry.
+type Package struct {
+	Registry          string `json:"registry"` // go, swift, crates or npm
+	Name              string `json:"name"`
+	Version           string `json:"version,omitempty"`
+	URL               string `json:"url"`
+	DocsURL           string `json:"docsUrl,omitempty"`
+	Downloads         int    `json:"downloads,omitempty"`
+	DownloadsPeriod   string `json:"downloadsPeriod,omitempty"`   // total or month
+	Dependents        int    `json:"dependents,omitempty"`        // packages in the registry that depend on it
+	DependentsUnknown bool   `json:"dependentsUnknown,omitempty"` // the registry has no reverse dependencies or they couldn't be read
+}
+
+// registry resolves a project's package from the manifest it publishes.
+// resolve returns nil when the package isn't published or belongs to a
+// different repository.
+type registry interface {
+	manifest() string
+	resolve(repo GitHubRepo, source string) (*Package, error)
+}
+
+func registries(config RegistryConfig) []registry {
+	config = config.withDefaults()
+	return []registry{
+		goModules{config},
+		swiftPackageIndex{config},
+		crates{config},
+		npm{config},
+	}
+}
+
+// Registries don't share the GitHub API's rate limits or credentials, and
+// their responses are cached apart from the API's so cache stats report them
+// separately
+var (
+	registryCacheDir = filepath.Join(".cache", "ct", "registries")
+	registryClient   = newClient(registryCacheDir)
+)
+
+// registryJSON fetches a registry resource into out. It reports false when
+// the registry doesn't know it.
+func registryJSON(resourceURL string, out interface{}) (bool, error) {
+	req, err := http.NewRequest("GET", resourceURL, nil)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	// crates.io rejects requests without a User-Agent
+	req.Header.Set("User-Agent", "guitaripod-website (https://github.com/"+githubUsername+")")
+
+	resp, err := registryClient.do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	// The Go proxy answers 410 Gone for modules it refuses to serve
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("%s responded with %d", req.URL.Host, resp.StatusCode)
+	}
+	return true, json.NewDecoder(resp.Body).Decode(out)
+}
+
+// sameRepository reports whether a repository URL from registry metadata,
+// like git+https://github.com/owner/name.git, points at the repo.
+func sameRepository(repositoryURL string, repo GitHubRepo) bool {
+	repositoryURL = strings.TrimSuffix(strings.ToLower(repositoryURL), ".git")
+	return strings.HasSuffix(repositoryURL, "/"+strings.ToLower(repo.fullName()))
+}
+
+// goModules reads the module path from go.mod, its latest version from the
+// module proxy and links the pkg.go.dev documentation. Neither reports
+// downloads.
+type goModules struct{ config RegistryConfig }
+
+var goModulePattern = regexp.MustCompile(`(?m)^module\s+"?([^"\s]+)"?`)
+
+func (goModules) manifest() string { return "go.mod" }
+
+func (r goModules) resolve(repo GitHubRepo, source string) (*Package, error) {
+	match := goModulePattern.FindStringSubmatch(source)
+	if match == nil {
+		return nil, nil
+	}
+	module := match[1]
+
+	var latest struct {
+		Version string `json:"Version"`
+	}
+	found, err := registryJSON(fmt.Sprintf("%s/%s/@latest", r.config.GoProxyURL, escapeModulePath(module)), &latest)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	docsURL := r.config.PkgGoDevURL + "/" + module
+	return &Package{
+		Registry: "go",
+		Name:     module,
+		Version:  latest.Version,
+		URL:      docsURL,
+		DocsURL:  docsURL,
+	}, nil
+}
+
+// escapeModulePath applies the module proxy's case encoding, which writes
+// each uppercase letter as ! followed by its lowercase form.
+func escapeModulePath(module string) string {
+	var b strings.Builder
+	for _, r := range module {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// swiftPackageIndex checks whether the repo is indexed. Packages are
+// identified by their repository, so the manifest itself isn't parsed, and
+// the version is the repo's latest release. Documentation is only linked
+// when the index hosts it, which the public endpoints don't reveal.
+type swiftPackageIndex struct{ config RegistryConfig }
+
+func (swiftPackageIndex) manifest() string { return "Package.swift" }
+
+func (r swiftPackageIndex) resolve(repo GitHubRepo, _ string) (*Package, error) {
+	// The shields.io badge endpoint is public, unlike the rest of the API
+	var badge struct {
+		Message string `json:"message"`
+	}
+	badgeURL := fmt.Sprintf("%s/api/packages/%s/badge?type=platforms", r.config.SwiftPackageIndexURL, repo.fullName())
+	found, err := registryJSON(badgeURL, &badge)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	return &Package{
+		Registry: "swift",
+		Name:     repo.fullName(),
+		URL:      r.config.SwiftPackageIndexURL + "/" + repo.fullName(),
+	}, nil
+}
+
+// crates reads the package name from Cargo.toml and its version and total
+// downloads from crates.io.
+type crates struct{ config RegistryConfig }
+
+var (
+	cargoPackageSection = regexp.MustCompile(`(?ms)^\[package\]\s*$(.*?)(?:^\[|\z)`)
+	cargoPackageName    = regexp.MustCompile(`(?m)^\s*name\s*=\s*"([^"]+)"`)
+)
+
+func (crates) manifest() string { return "Cargo.toml" }
+
+func (r crates) resolve(repo GitHubRepo, source string) (*Package, error) {
+	// Virtual workspace manifests have no [package]
+	section := cargoPackageSection.FindStringSubmatch(source)
+	if section == nil {
+		return nil, nil
+	}
+	name := cargoPackageName.FindStringSubmatch(section[1])
+	if name == nil {
+		return nil, nil
+	}
+
+	var data struct {
+		Crate struct {
+			Name             string `json:"name"`
+			MaxStableVersion string `json:"max_stable_version"`
+			NewestVersion    string `json:"newest_version"`
+			Downloads        int    `json:"downloads"`
+			Documentation    string `json:"documentation"`
+			Repository       string `json:"repository"`
+		} `json:"crate"`
+	}
+	found, err := registryJSON(fmt.Sprintf("%s/api/v1/crates/%s", r.config.CratesURL, url.PathEscape(name[1])), &data)
+	if err != nil || !found {
+		return nil, err
+	}
+	crate := data.Crate
+	if !sameRepository(crate.Repository, repo) {
+		return nil, nil
+	}
+
+	version := crate.MaxStableVersion
+	if version == "" {
+		version = crate.NewestVersion
+	}
+	docsURL := crate.Documentation
+	if docsURL == "" {
+		docsURL = r.config.DocsRsURL + "/" + crate.Name
+	}
+	return &Package{
+		Registry:        "crates",
+		Name:            crate.Name,
+		Version:         version,
+		URL:             r.config.CratesURL + "/crates/" + crate.Name,
+		DocsURL:         docsURL,
+		Downloads:       crate.Downloads,
+		DownloadsPeriod: "total",
+	}, nil
+}
+
+// npm reads the package name from package.json and its latest version and
+// last month's downloads from the npm registry.
+type npm struct{ config RegistryConfig }
+
+func (npm) manifest() string { return "package.json" }
+
+func (r npm) resolve(repo GitHubRepo, source string) (*Package, error) {
+	var manifest struct {
+		Name    string `json:"name"`
+		Private bool   `json:"private"`
+	}
+	if err := json.Unmarshal([]byte(source), &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse package.json: %w", err)
+	}
+	if manifest.Name == "" || manifest.Private {
+		return nil, nil
+	}
+
+	// Scoped names keep the @ but escape the slash
+	escaped := strings.Replace(url.PathEscape(manifest.Name), "%40", "@", 1)
+
+	var data struct {
+		DistTags struct {
+			Latest string `json:"latest"`
+		} `json:"dist-tags"`
+		Repository json.RawMessage `json:"repository"`
+	}
+	found, err := registryJSON(r.config.NPMRegistryURL+"/"+escaped, &data)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	// repository is either a URL or an object with one
+	var repositoryURL string
+	if json.Unmarshal(data.Repository, &repositoryURL) != nil {
+		var repository struct {
+			URL string `json:"url"`
+		}
+		if json.Unmarshal(data.Repository, &repository) == nil {
+			repositoryURL = repository.URL
+		}
+	}
+	if !sameRepository(repositoryURL, repo) {
+		return nil, nil
+	}
+
+	pkg := &Package{
+		Registry: "npm",
+		Name:     manifest.Name,
+		Version:  data.DistTags.Latest,
+		URL:      r.config.NPMWebURL + "/package/" + manifest.Name,
+	}
+
+	var downloads struct {
+		Downloads int `json:"downloads"`
+	}
+	if found, err := registryJSON(r.config.NPMDownloadsURL+"/downloads/point/last-month/"+manifest.Name, &downloads); err != nil {
+		fmt.Printf("  ⚠️  %s: couldn't read npm downloads: %v\n", repo.Name, err)
+	} else if found {
+		pkg.Downloads = downloads.Downloads
+		pkg.DownloadsPeriod = "month"
+	}
+	return pkg, nil
+}
+
+// resolvePackages looks up the repo in every registry whose manifest is in
+// its root.
+func resolvePackages(repo GitHubRepo, adapters []registry) ([]Package, error) {
+	entries, err := getContents(repo.fullName(), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repository root: %w", err)
+	}
+
+	packages := []Package{}
+	for _, adapter := range adapters {
+		if !containsEntry(entries, adapter.manifest()) {
+			continue
+		}
+		source, err := getFileContent(repo.fullName(), adapter.manifest())
+		if err != nil {
+			fmt.Printf("  ⚠️  %s: couldn't read %s: %v\n", repo.Name, adapter.manifest(), err)
+			continue
+		}
+		pkg, err := adapter.resolve(repo, source)
+		if err != nil {
+			fmt.Printf("  ⚠️  %s: couldn't look up %s: %v\n", repo.Name, adapter.manifest(), err)
+			continue
+		}
+		if pkg != nil {
+			packages = append(packages, *pkg)
+		}
+	}
+	return packages, nil
+}
+
+func containsEntry(entries []contentEntry, name string) bool {
+	for _, entry := range entries {
+		if entry.Type == "file" && entry.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// addPackages records registry listings for the projects. Projects carried
+// from the previous run keep the listings found then. Projects without any,
+// including those that couldn't be resolved, get an empty list.
+func addPackages(projects []Project, config RegistryConfig) {
+	adapters := registries(config)
+	parallel(len(projects), func(i int) {
+		if projects[i].Packages == nil {
+			projects[i].Packages = []Package{}
+		}
+		if projects[i].carried {
+			return
+		}
+		repo := GitHubRepo{Name: projects[i].Name, FullName: projects[i].Owner + "/" + projects[i].Name}
+		packages, err := resolvePackages(repo, adapters)
+		if err != nil {
+			fmt.Printf("  ⚠️  %s: couldn't resolve packages: %v\n", projects[i].Name, err)
+			return
+		}
+		for j, pkg := range packages {
+			if pkg.Version == "" && projects[i].LatestRelease != nil {
+				packages[j].Version = projects[i].LatestRelease.Tag
+				pkg.Version = packages[j].Version
+			}
+			fmt.Printf("  📦 %s: %s %s %s\n", projects[i].Name, pkg.Registry, pkg.Name, pkg.Version)
+		}
+		projects[i].Packages = packages
+	})
+}
@@ -0,0 +1,287 @@
+package github
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// response is a canned answer of the fake server. A zero Status means 200.
+type response struct {
+	Status int
+	Header map[string]string
+	Body   string
+}
+
+// fakeServer answers each request URI in routes and everything else with
+// 404. For the duration of the test, the GitHub API and registry clients talk
+// to it without caching or credentials.
+func fakeServer(t *testing.T, routes map[string]response) *httptest.Server {
+	t.Helper()
+
+	return fakeHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, ok := routes[r.URL.RequestURI()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		for name, value := range resp.Header {
+			w.Header().Set(name, value)
+		}
+		if resp.Status != 0 {
+			w.WriteHeader(resp.Status)
+		}
+		io.WriteString(w, resp.Body)
+	}))
+}
+
+// fakeHandler is fakeServer for tests that need to answer requests
+// themselves.
+func fakeHandler(t *testing.T, handler http.Handler) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	api, registry, base := apiClient, registryClient, apiBaseURL
+	apiClient = &client{http: srv.Client(), remaining: -1}
+	registryClient = &client{http: srv.Client(), remaining: -1}
+	apiBaseURL = srv.URL
+	t.Cleanup(func() {
+		apiClient, registryClient, apiBaseURL = api, registry, base
+	})
+	return srv
+}
+
+// fakeRegistries points every registry at the fake server.
+func fakeRegistries(baseURL string) RegistryConfig {
+	return RegistryConfig{
+		GoProxyURL:           baseURL,
+		PkgGoDevURL:          baseURL,
+		SwiftPackageIndexURL: baseURL,
+		CratesURL:            baseURL,
+		DocsRsURL:            baseURL,
+		NPMRegistryURL:       baseURL,
+		NPMDownloadsURL:      baseURL,
+		NPMWebURL:            baseURL,
+		DepsDevURL:           baseURL,
+	}.withDefaults()
+}
+
+func TestGoModulesResolve(t *testing.T) {
+	srv := fakeServer(t, map[string]response{
+		"/github.com/guitaripod/!c!t/@latest": {Body: `{"Version":"v1.2.0","Time":"2025-01-01T00:00:00Z"}`},
+		"/github.com/guitaripod/gone/@latest": {Status: http.StatusGone, Body: `not found`},
+	})
+	adapter := goModules{fakeRegistries(srv.URL)}
+
+	tests := []struct {
+		name   string
+		source string
+		want   *Package
+	}{
+		{
+			name:   "published",
+			source: "module github.com/guitaripod/CT\n\ngo 1.24\n",
+			want: &Package{
+				Registry: "go",
+				Name:     "github.com/guitaripod/CT",
+				Version:  "v1.2.0",
+				URL:      srv.URL + "/github.com/guitaripod/CT",
+				DocsURL:  srv.URL + "/github.com/guitaripod/CT",
+			},
+		},
+		{name: "refused by the proxy", source: "module github.com/guitaripod/gone\n"},
+		{name: "unknown", source: "module github.com/guitaripod/missing\n"},
+		{name: "no module directive", source: "go 1.24\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := adapter.resolve(GitHubRepo{FullName: "guitaripod/ct"}, tt.source)
+			if err != nil {
+				t.Fatalf("resolve: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolve = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSwiftPackageIndexResolve(t *testing.T) {
+	srv := fakeServer(t, map[string]response{
+		"/api/packages/guitaripod/GeminiKit/badge?type=platforms": {Body: `{"schemaVersion":1,"label":"Platforms","message":"iOS | macOS | Linux"}`},
+	})
+	adapter := swiftPackageIndex{fakeRegistries(srv.URL)}
+
+	got, err := adapter.resolve(GitHubRepo{FullName: "guitaripod/GeminiKit"}, "")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	want := &Package{Registry: "swift", Name: "guitaripod/GeminiKit", URL: srv.URL + "/guitaripod/GeminiKit"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("resolve = %+v, want %+v", got, want)
+	}
+
+	got, err = adapter.resolve(GitHubRepo{FullName: "guitaripod/unindexed"}, "")
+	if err != nil || got != nil {
+		t.Errorf("resolve unindexed = %+v, %v, want nil, nil", got, err)
+	}
+}
+
+func TestCratesResolve(t *testing.T) {
+	srv := fakeServer(t, map[string]response{
+		"/api/v1/crates/nasa-rs": {Body: `{"crate":{"name":"nasa-rs","max_stable_version":"0.3.0","newest_version":"0.4.0-beta.1",
+			"downloads":1200,"documentation":null,"repository":"https://github.com/guitaripod/nasa-rs"}}`},
+		"/api/v1/crates/taken": {Body: `{"crate":{"name":"taken","newest_version":"1.0.0","repository":"https://github.com/someone-else/taken"}}`},
+	})
+	adapter := crates{fakeRegistries(srv.URL)}
+
+	tests := []struct {
+		name   string
+		repo   string
+		source string
+		want   *Package
+	}{
+		{
+			name:   "published",
+			repo:   "guitaripod/nasa-rs",
+			source: "[package]\nname = \"nasa-rs\"\nversion = \"0.3.0\"\n\n[dependencies]\nname = \"not-this\"\n",
+			want: &Package{
+				Registry:        "crates",
+				Name:            "nasa-rs",
+				Version:         "0.3.0",
+				URL:             srv.URL + "/crates/nasa-rs",
+				DocsURL:         srv.URL + "/nasa-rs",
+				Downloads:       1200,
+				DownloadsPeriod: "total",
+			},
+		},
+		{name: "name owned by another repository", repo: "guitaripod/taken", source: "[package]\nname = \"taken\"\n"},
+		{name: "unpublished", repo: "guitaripod/local", source: "[package]\nname = \"local\"\n"},
+		{name: "virtual workspace", repo: "guitaripod/workspace", source: "[workspace]\nmembers = [\"a\", \"b\"]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := adapter.resolve(GitHubRepo{FullName: tt.repo}, tt.source)
+			if err != nil {
+				t.Fatalf("resolve: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolve = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNPMResolve(t *testing.T) {
+	srv := fakeServer(t, map[string]response{
+		"/@guitaripod%2Fwidget": {Body: `{"dist-tags":{"latest":"2.0.0"},
+			"repository":{"type":"git","url":"git+https://github.com/guitaripod/widget.git"}}`},
+		"/downloads/point/last-month/@guitaripod/widget": {Body: `{"downloads":340,"package":"@guitaripod/widget"}`},
+		"/plain":    {Body: `{"dist-tags":{"latest":"1.0.0"},"repository":"https://github.com/guitaripod/plain"}`},
+		"/squatted": {Body: `{"dist-tags":{"latest":"9.9.9"},"repository":"https://github.com/someone-else/squatted"}`},
+	})
+	adapter := npm{fakeRegistries(srv.URL)}
+
+	tests := []struct {
+		name    string
+		repo    string
+		source  string
+		want    *Package
+		wantErr bool
+	}{
+		{
+			name:   "scoped with downloads",
+			repo:   "guitaripod/widget",
+			source: `{"name":"@guitaripod/widget","version":"2.0.0"}`,
+			want: &Package{
+				Registry:        "npm",
+				Name:            "@guitaripod/widget",
+				Version:         "2.0.0",
+				URL:             srv.URL + "/package/@guitaripod/widget",
+				Downloads:       340,
+				DownloadsPeriod: "month",
+			},
+		},
+		{
+			name:   "repository as a string, no downloads",
+			repo:   "guitaripod/plain",
+			source: `{"name":"plain"}`,
+			want:   &Package{Registry: "npm", Name: "plain", Version: "1.0.0", URL: srv.URL + "/package/plain"},
+		},
+		{name: "name owned by another repository", repo: "guitaripod/squatted", source: `{"name":"squatted"}`},
+		{name: "private", repo: "guitaripod/site", source: `{"name":"site","private":true}`},
+		{name: "unpublished", repo: "guitaripod/local", source: `{"name":"local"}`},
+		{name: "invalid manifest", repo: "guitaripod/broken", source: `{"name":`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := adapter.resolve(GitHubRepo{FullName: tt.repo}, tt.source)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolve error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolve = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryJSONServerError(t *testing.T) {
+	srv := fakeServer(t, map[string]response{
+		"/broken": {Status: http.StatusInternalServerError, Body: `{}`},
+	})
+
+	var out struct{}
+	if _, err := registryJSON(srv.URL+"/broken", &out); err == nil {
+		t.Error("registryJSON returned no error for a 500 response")
+	}
+}
+
+func TestAddPackagesUnresolved(t *testing.T) {
+	srv := fakeServer(t, nil)
+
+	projects := []Project{{Name: "private-repo", Owner: "guitaripod"}}
+	addPackages(projects, fakeRegistries(srv.URL))
+
+	if projects[0].Packages == nil || len(projects[0].Packages) != 0 {
+		t.Errorf("Packages = %#v, want an empty, non-nil slice", projects[0].Packages)
+	}
+}
+
+func TestEscapeModulePath(t *testing.T) {
+	tests := map[string]string{
+		"github.com/guitaripod/ct":        "github.com/guitaripod/ct",
+		"github.com/BurntSushi/toml":      "github.com/!burnt!sushi/toml",
+		"github.com/guitaripod/SwiftyGPT": "github.com/guitaripod/!swifty!g!p!t",
+	}
+	for module, want := range tests {
+		if got := escapeModulePath(module); got != want {
+			t.Errorf("escapeModulePath(%q) = %q, want %q", module, got, want)
+		}
+	}
+}
+
+func TestSameRepository(t *testing.T) {
+	repo := GitHubRepo{FullName: "guitaripod/Widget"}
+	tests := map[string]bool{
+		"https://github.com/guitaripod/widget":         true,
+		"git+https://github.com/guitaripod/Widget.git": true,
+		"git://github.com/guitaripod/widget.git":       true,
+		"https://github.com/guitaripod/widget-extras":  false,
+		"https://github.com/someone/widget":            false,
+		"":                                             false,
+	}
+	for repositoryURL, want := range tests {
+		if got := sameRepository(repositoryURL, repo); got != want {
+			t.Errorf("sameRepository(%q) = %v, want %v", repositoryURL, got, want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Release describes the most recently published release of a project.
+type Release struct {
+	Tag         string `json:"tag"`
+	Name        string `json:"name"`
+	PublishedAt string `json:"publishedAt"`
+	Prerelease  bool   `json:"prerelease"`
+}
+
+type releaseSummary struct {
+	Count     int
+	Latest    *Release
+	Downloads int
+	Assets    []string // file names attached to the latest release
+}
+
+type gitHubRelease struct {
+	TagName     string         `json:"tag_name"`
+	Name        string         `json:"name"`
+	Draft       bool           `json:"draft"`
+	Prerelease  bool           `json:"prerelease"`
+	PublishedAt string         `json:"published_at"`
+	Assets      []releaseAsset `json:"assets"`
+}
+
+type releaseAsset struct {
+	Name          string `json:"name"`
+	DownloadCount int    `json:"download_count"`
+}
+
+// getReleases pages through every published release of the repo, returning
+// the count, the newest release and asset downloads summed across releases.
+func getReleases(repo GitHubRepo) (releaseSummary, error) {
+	var summary releaseSummary
+
+	url := fmt.Sprintf("%s/repos/%s/releases?per_page=100", apiBaseURL, repo.fullName())
+	for url != "" {
+		releases, next, err := fetchReleasePage(url)
+		if err != nil {
+			return summary, err
+		}
+
+		for _, r := range releases {
+			// Drafts are only visible with push access and aren't public
+			if r.Draft {
+				continue
+			}
+
+			summary.Count++
+			for _, asset := range r.Assets {
+				summary.Downloads += asset.DownloadCount
+			}
+			if summary.Latest == nil || r.PublishedAt > summary.Latest.PublishedAt {
+				summary.Latest = &Release{
+					Tag:         r.TagName,
+					Name:        r.Name,
+					PublishedAt: r.PublishedAt,
+					Prerelease:  r.Prerelease,
+				}
+				summary.Assets = assetNames(r.Assets)
+			}
+		}
+		url = next
+	}
+
+	return summary, nil
+}
+
+func fetchReleasePage(url string) ([]gitHubRelease, string, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Set headers
+	req.Header.Set("User-Agent", "compiledthoughts-static-site")
+
+	resp, err := apiClient.do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Return 0 releases for 404 (no releases) instead of error
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("GitHub API error: %s", resp.Status)
+	}
+
+	var releases []gitHubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, "", err
+	}
+
+	return releases, parseLinkHeader(resp.Header.Get("Link"))["next"], nil
+}
+
+// getReleaseAssets lists the file names attached to the release with the
+// given tag.
+func getReleaseAssets(repo GitHubRepo, tag string) ([]string, error) {
+	var release gitHubRelease
+	releaseURL := fmt.Sprintf("%s/repos/%s/releases/tags/%s", apiBaseURL, repo.fullName(), url.PathEscape(tag))
+	if err := getJSON(releaseURL, &release); err != nil {
+		return nil, err
+	}
+	return assetNames(release.Assets), nil
+}
+
+func assetNames(assets []releaseAsset) []string {
+	names := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		names = append(names, asset.Name)
+	}
+	return names
+}
@@ -0,0 +1,42 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetReleases(t *testing.T) {
+	routes := make(map[string]response)
+	srv := fakeServer(t, routes)
+	routes["/repos/guitaripod/ct/releases?per_page=100"] = response{
+		Header: map[string]string{"Link": `<` + srv.URL + `/repos/guitaripod/ct/releases?per_page=100&page=2>; rel="next"`},
+		Body: `[
+			{"tag_name":"v2.0.0","draft":true,"assets":[{"name":"ct_linux_amd64.tar.gz","download_count":0}]},
+			{"tag_name":"v1.1.0","name":"Patch","published_at":"2025-02-01T00:00:00Z",
+				"assets":[{"name":"ct_linux_amd64.tar.gz","download_count":30},{"name":"ct_darwin_arm64.tar.gz","download_count":20}]}
+		]`,
+	}
+	routes["/repos/guitaripod/ct/releases?per_page=100&page=2"] = response{
+		Body: `[{"tag_name":"v1.0.0","published_at":"2025-01-01T00:00:00Z","prerelease":true,
+			"assets":[{"name":"ct.tar.gz","download_count":5}]}]`,
+	}
+
+	summary, err := getReleases(GitHubRepo{FullName: "guitaripod/ct"})
+	if err != nil {
+		t.Fatalf("getReleases: %v", err)
+	}
+	want := releaseSummary{
+		Count:     2,
+		Latest:    &Release{Tag: "v1.1.0", Name: "Patch", PublishedAt: "2025-02-01T00:00:00Z"},
+		Downloads: 55,
+		Assets:    []string{"ct_linux_amd64.tar.gz", "ct_darwin_arm64.tar.gz"},
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("getReleases = %+v, want %+v", summary, want)
+	}
+
+	summary, err = getReleases(GitHubRepo{FullName: "guitaripod/no-releases"})
+	if err != nil || summary.Count != 0 || summary.Latest != nil {
+		t.Errorf("getReleases without releases = %+v, %v", summary, err)
+	}
+}
@@ -0,0 +1,298 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const rulesPath = "config/github-rules.json"
+
+// Rules decides a project's category and highlights. Categories go to the
+// highest-priority matching rule; every matching highlight rule contributes,
+// in priority order, followed by the first few topics.
+type Rules struct {
+	DefaultCategory string `json:"defaultCategory"`
+	MaxHighlights   int    `json:"maxHighlights"`
+	TopicHighlights int    `json:"topicHighlights"`
+	Categories      []Rule `json:"categories"`
+	Highlights      []Rule `json:"highlights"`
+}
+
+// Rule fires when every condition in All matches and, if Any is set, at
+// least one condition in Any matches.
+type Rule struct {
+	ID        string      `json:"id"`
+	Category  string      `json:"category,omitempty"`
+	Highlight string      `json:"highlight,omitempty"`
+	Priority  int         `json:"priority"`
+	All       []Condition `json:"all,omitempty"`
+	Any       []Condition `json:"any,omitempty"`
+}
+
+// Condition matches when every field that is set matches. Topics and
+// language match if the repo has any of the listed values; name and
+// description are case-insensitive regular expressions.
+type Condition struct {
+	Topics      []string `json:"topics,omitempty"`
+	Language    []string `json:"language,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+
+	name        *regexp.Regexp
+	description *regexp.Regexp
+}
+
+func loadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := rules.compile(); err != nil {
+		return nil, fmt.Errorf("invalid rules in %s: %w", path, err)
+	}
+	return &rules, nil
+}
+
+func (r *Rules) compile() error {
+	if r.DefaultCategory == "" {
+		return fmt.Errorf("defaultCategory is required")
+	}
+
+	for i := range r.Categories {
+		if r.Categories[i].Category == "" {
+			return fmt.Errorf("category rule %q has no category", r.Categories[i].ID)
+		}
+		if err := r.Categories[i].compile(); err != nil {
+			return err
+		}
+	}
+	for i := range r.Highlights {
+		if r.Highlights[i].Highlight == "" {
+			return fmt.Errorf("highlight rule %q has no highlight", r.Highlights[i].ID)
+		}
+		if err := r.Highlights[i].compile(); err != nil {
+			return err
+		}
+	}
+
+	// Stable sorts keep file order for rules of equal priority
+	byPriority := func(rules []Rule) {
+		sort.SliceStable(rules, func(i, j int) bool {
+			return rules[i].Priority > rules[j].Priority
+		})
+	}
+	byPriority(r.Categories)
+	byPriority(r.Highlights)
+	return nil
+}
+
+func (rule *Rule) compile() error {
+	if rule.ID == "" {
+		return fmt.Errorf("rule without id")
+	}
+	if len(rule.All) == 0 && len(rule.Any) == 0 {
+		return fmt.Errorf("rule %q has no conditions", rule.ID)
+	}
+
+	for _, conditions := range [][]Condition{rule.All, rule.Any} {
+		for i := range conditions {
+			c := &conditions[i]
+			if len(c.Topics) == 0 && len(c.Language) == 0 && c.Name == "" && c.Description == "" {
+				return fmt.Errorf("rule %q has an empty condition", rule.ID)
+			}
+
+			var err error
+			if c.Name != "" {
+				if c.name, err = regexp.Compile("(?i)" + c.Name); err != nil {
+					return fmt.Errorf("rule %q: bad name pattern: %w", rule.ID, err)
+				}
+			}
+			if c.Description != "" {
+				if c.description, err = regexp.Compile("(?i)" + c.Description); err != nil {
+					return fmt.Errorf("rule %q: bad description pattern: %w", rule.ID, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (rule Rule) matches(repo GitHubRepo) bool {
+	for _, c := range rule.All {
+		if !c.matches(repo) {
+			return false
+		}
+	}
+	if len(rule.Any) == 0 {
+		return true
+	}
+	for _, c := range rule.Any {
+		if c.matches(repo) {
+			return true
+		}
+	}
+	return false
+}
+
+// topics lists every topic the rule's conditions look for.
+func (rule Rule) topics() []string {
+	var topics []string
+	for _, conditions := range [][]Condition{rule.All, rule.Any} {
+		for _, c := range conditions {
+			topics = append(topics, c.Topics...)
+		}
+	}
+	return topics
+}
+
+func (c Condition) matches(repo GitHubRepo) bool {
+	if len(c.Topics) > 0 {
+		found := false
+		for _, topic := range c.Topics {
+			if containsIgnoreCase(repo.Topics, topic) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(c.Language) > 0 && !containsIgnoreCase(c.Language, repo.Language) {
+		return false
+	}
+	if c.name != nil && !c.name.MatchString(repo.Name) {
+		return false
+	}
+	if c.description != nil && !c.description.MatchString(repo.Description) {
+		return false
+	}
+	return true
+}
+
+// category returns the category and the id of the rule that chose it. The
+// id is empty when no rule matched and the default applies.
+func (r *Rules) category(repo GitHubRepo) (string, string) {
+	for _, rule := range r.Categories {
+		if rule.matches(repo) {
+			return rule.Category, rule.ID
+		}
+	}
+	return r.DefaultCategory, ""
+}
+
+func (r *Rules) highlights(repo GitHubRepo) []string {
+	highlights := []string{}
+	// Topics a matching rule looks for are already represented by its
+	// highlight, so "ai" doesn't also show up next to "AI-powered"
+	var covered []string
+	for _, rule := range r.Highlights {
+		if !rule.matches(repo) {
+			continue
+		}
+		covered = append(covered, rule.topics()...)
+		if !containsIgnoreCase(highlights, rule.Highlight) {
+			highlights = append(highlights, rule.Highlight)
+		}
+	}
+
+	for i, topic := range repo.Topics {
+		if i >= r.TopicHighlights {
+			break
+		}
+		if !containsIgnoreCase(highlights, topic) && !containsIgnoreCase(covered, topic) {
+			highlights = append(highlights, titleCase(topic))
+		}
+	}
+
+	if r.MaxHighlights > 0 && len(highlights) > r.MaxHighlights {
+		return highlights[:r.MaxHighlights]
+	}
+	return highlights
+}
+
+// titleCase upper-cases the first letter of each hyphen or space separated
+// word, matching the deprecated strings.Title for topic names.
+func titleCase(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		if i == 0 || runes[i-1] == '-' || runes[i-1] == ' ' {
+			runes[i] = unicode.ToUpper(r)
+		}
+	}
+	return string(runes)
+}
+
+// ExplainCategory fetches a single repository and prints how the rules
+// categorize it: every category rule's outcome, the one that fired and the
+// resulting highlights.
+func ExplainCategory(name string) error {
+	rules, err := loadRules(rulesPath)
+	if err != nil {
+		return err
+	}
+	overrides, err := loadOverrides(overridesPath)
+	if err != nil {
+		return err
+	}
+	if _, err := loadConfig(configPath); err != nil {
+		return err
+	}
+	if apiClient.auth, err = loadCredentials(); err != nil {
+		return err
+	}
+
+	// Bare names refer to the user's own repos
+	fullName := name
+	if !strings.Contains(name, "/") {
+		fullName = githubUsername + "/" + name
+	}
+
+	var repo GitHubRepo
+	if err := getJSON(fmt.Sprintf("%s/repos/%s", apiBaseURL, fullName), &repo); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", name, err)
+	}
+
+	fmt.Printf("%s\n", repo.fullName())
+	fmt.Printf("  Language: %s\n", repo.Language)
+	fmt.Printf("  Topics: %s\n", strings.Join(repo.Topics, ", "))
+	fmt.Printf("  Description: %s\n\n", repo.Description)
+
+	category, fired := rules.category(repo)
+
+	fmt.Println("Category rules (highest priority first):")
+	for _, rule := range rules.Categories {
+		mark := "✗"
+		if rule.matches(repo) {
+			mark = "✓"
+		}
+		fmt.Printf("  %s [%d] %s → %s\n", mark, rule.Priority, rule.ID, rule.Category)
+	}
+
+	if fired == "" {
+		fmt.Printf("\nNo rule matched, using default category: %s\n", category)
+	} else {
+		fmt.Printf("\nRule %q fired: %s\n", fired, category)
+	}
+	if override := overrides[repo.projectID()]; override.Category != "" {
+		fmt.Printf("Overridden in %s: %s\n", overridesPath, override.Category)
+	}
+
+	highlights := rules.highlights(repo)
+	if len(highlights) == 0 {
+		fmt.Println("Highlights: none")
+	} else {
+		fmt.Printf("Highlights: %s\n", strings.Join(highlights, ", "))
+	}
+	return nil
+}
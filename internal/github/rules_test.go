@@ -0,0 +1,168 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+)
+
+func shippedRules(t *testing.T) *Rules {
+	t.Helper()
+	rules, err := loadRules("../../" + rulesPath)
+	if err != nil {
+		t.Fatalf("loadRules: %v", err)
+	}
+	return rules
+}
+
+func TestCategory(t *testing.T) {
+	rules := shippedRules(t)
+
+	tests := []struct {
+		name string
+		repo GitHubRepo
+		want string
+		rule string
+	}{
+		{
+			name: "cli suffix",
+			repo: GitHubRepo{Name: "apod-cli", Language: "Rust"},
+			want: "CLI Tools", rule: "cli",
+		},
+		{
+			name: "cli outranks swift package",
+			repo: GitHubRepo{Name: "ToolKit", Language: "Swift", Description: "A CLI built on a Swift package"},
+			want: "CLI Tools", rule: "cli",
+		},
+		{
+			name: "swift package needs swift",
+			repo: GitHubRepo{Name: "PixelKit", Language: "Swift"},
+			want: "Swift Packages", rule: "swift-package",
+		},
+		{
+			name: "kit suffix in another language",
+			repo: GitHubRepo{Name: "geminikit", Language: "Go"},
+			want: "Go Projects", rule: "go",
+		},
+		{
+			name: "gtk desktop app",
+			repo: GitHubRepo{Name: "sonar", Language: "Rust", Description: "A GTK4 music player"},
+			want: "Desktop Apps", rule: "desktop-app",
+		},
+		{
+			name: "app alone is not desktop",
+			repo: GitHubRepo{Name: "pantry", Language: "Swift", Description: "An iOS app to track groceries"},
+			want: "Swift Projects", rule: "swift",
+		},
+		{
+			name: "cli needs a word boundary",
+			repo: GitHubRepo{Name: "clipboard", Language: "Go", Description: "Clipboard history client"},
+			want: "Go Projects", rule: "go",
+		},
+		{
+			name: "default",
+			repo: GitHubRepo{Name: "lastfm-rs", Language: "Rust", Description: "Last.fm API wrapper"},
+			want: "Other Projects",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rule := rules.category(tt.repo)
+			if got != tt.want || rule != tt.rule {
+				t.Errorf("category = %q (rule %q), want %q (rule %q)", got, rule, tt.want, tt.rule)
+			}
+		})
+	}
+}
+
+func TestRulePriorityAndConditions(t *testing.T) {
+	rules := &Rules{
+		DefaultCategory: "Other",
+		Categories: []Rule{
+			{ID: "low", Category: "Low", Priority: 1, Any: []Condition{{Language: []string{"Go"}}}},
+			{ID: "tie-first", Category: "First", Priority: 5, Any: []Condition{{Topics: []string{"tie"}}}},
+			{ID: "tie-second", Category: "Second", Priority: 5, Any: []Condition{{Topics: []string{"tie"}}}},
+			{
+				ID: "both", Category: "Both", Priority: 10,
+				All: []Condition{{Language: []string{"go"}}},
+				Any: []Condition{{Name: "^x"}, {Topics: []string{"both"}}},
+			},
+		},
+	}
+	if err := rules.compile(); err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		repo GitHubRepo
+		want string
+	}{
+		{"all and one of any", GitHubRepo{Name: "xray", Language: "Go"}, "Both"},
+		{"all and the other any", GitHubRepo{Name: "ray", Language: "Go", Topics: []string{"Both"}}, "Both"},
+		{"all without any", GitHubRepo{Name: "ray", Language: "Go"}, "Low"},
+		{"any without all", GitHubRepo{Name: "xray", Language: "Rust", Topics: []string{"both"}}, "Other"},
+		{"equal priority keeps file order", GitHubRepo{Name: "ray", Topics: []string{"tie"}}, "First"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := rules.category(tt.repo); got != tt.want {
+				t.Errorf("category = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlights(t *testing.T) {
+	rules := shippedRules(t)
+
+	tests := []struct {
+		name string
+		repo GitHubRepo
+		want []string
+	}{
+		{
+			name: "ai needs a word boundary",
+			repo: GitHubRepo{Description: "Helps maintain every detail of your dotfiles"},
+			want: []string{},
+		},
+		{
+			name: "ai description",
+			repo: GitHubRepo{Description: "Generate images with DALL-E"},
+			want: []string{"AI-powered"},
+		},
+		{
+			name: "topic behind a rule isn't repeated",
+			repo: GitHubRepo{Description: "An AI assistant", Topics: []string{"ai", "rust"}},
+			want: []string{"AI-powered", "Rust"},
+		},
+		{
+			name: "topic matching a highlight isn't repeated",
+			repo: GitHubRepo{Description: "Runs on Linux", Topics: []string{"cross-platform", "swift"}},
+			want: []string{"Cross-platform", "Swift"},
+		},
+		{
+			name: "priority order and limit",
+			repo: GitHubRepo{
+				Language:    "Swift",
+				Description: "Tested async GTK app for Linux, with homebrew",
+				Topics:      []string{"music"},
+			},
+			want: []string{"Homebrew available", "Cross-platform", "Well-tested"},
+		},
+		{
+			name: "topics fill up after rules",
+			repo: GitHubRepo{Description: "Vim controls", Topics: []string{"terminal-ui", "rust", "tui"}},
+			want: []string{"Vim controls", "Terminal-Ui", "Rust"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules.highlights(tt.repo); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("highlights = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
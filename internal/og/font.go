@@ -0,0 +1,292 @@
+package og
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strconv"
+	"strings"
+)
+
+// A 5x7 pixel font with a descender row, scaled up by whole pixels so the
+// cards keep the site's terminal look without a TrueType rasterizer. It only
+// covers printable ASCII; Render rejects text with anything else.
+//
+//go:embed font.bdf
+var fontBDF string
+
+type glyph struct {
+	advance int
+	width   int
+	height  int
+	xOffset int
+	yOffset int // from the baseline to the bottom row
+	rows    []uint32
+}
+
+type bitmapFont struct {
+	ascent  int
+	descent int
+	glyphs  map[rune]glyph
+}
+
+var pixelFont = mustParseBDF(fontBDF)
+
+func mustParseBDF(source string) *bitmapFont {
+	font, err := parseBDF(source)
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded font: %v", err))
+	}
+	return font
+}
+
+// parseBDF reads the subset of the Glyph Bitmap Distribution Format the
+// embedded font uses: ascent and descent properties and, per glyph, the
+// encoding, advance, bounding box and bitmap rows.
+func parseBDF(source string) (*bitmapFont, error) {
+	font := &bitmapFont{glyphs: make(map[rune]glyph)}
+
+	var (
+		current  glyph
+		encoding = -1
+		inBitmap bool
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(source))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		ints := func(count int) ([]int, error) {
+			if len(fields) < count+1 {
+				return nil, fmt.Errorf("line %d: %s needs %d values", line, fields[0], count)
+			}
+			values := make([]int, count)
+			for i := range values {
+				v, err := strconv.Atoi(fields[i+1])
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", line, err)
+				}
+				values[i] = v
+			}
+			return values, nil
+		}
+
+		if inBitmap {
+			if fields[0] == "ENDCHAR" {
+				inBitmap = false
+				if encoding >= 0 {
+					font.glyphs[rune(encoding)] = current
+				}
+				continue
+			}
+			row, err := strconv.ParseUint(fields[0], 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid bitmap row: %w", line, err)
+			}
+			// Rows are padded to whole bytes, left-aligned
+			current.rows = append(current.rows, uint32(row)<<(32-4*len(fields[0])))
+			continue
+		}
+
+		switch fields[0] {
+		case "FONT_ASCENT", "FONT_DESCENT":
+			v, err := ints(1)
+			if err != nil {
+				return nil, err
+			}
+			if fields[0] == "FONT_ASCENT" {
+				font.ascent = v[0]
+			} else {
+				font.descent = v[0]
+			}
+		case "STARTCHAR":
+			current, encoding = glyph{}, -1
+		case "ENCODING":
+			v, err := ints(1)
+			if err != nil {
+				return nil, err
+			}
+			encoding = v[0]
+		case "DWIDTH":
+			v, err := ints(1)
+			if err != nil {
+				return nil, err
+			}
+			current.advance = v[0]
+		case "BBX":
+			v, err := ints(4)
+			if err != nil {
+				return nil, err
+			}
+			current.width, current.height, current.xOffset, current.yOffset = v[0], v[1], v[2], v[3]
+		case "BITMAP":
+			inBitmap = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if font.ascent == 0 || len(font.glyphs) == 0 {
+		return nil, fmt.Errorf("no glyphs or ascent")
+	}
+	if _, ok := font.glyphs['?']; !ok {
+		return nil, fmt.Errorf("missing fallback glyph '?'")
+	}
+	return font, nil
+}
+
+// glyph returns the glyph for r, falling back to '?' for characters lookup
+// can't find.
+func (f *bitmapFont) glyph(r rune) glyph {
+	if g, ok := f.lookup(r); ok {
+		return g
+	}
+	return f.glyphs['?']
+}
+
+// lookup returns the glyph for r, approximating common typographic characters
+// the font lacks.
+func (f *bitmapFont) lookup(r rune) (glyph, bool) {
+	if g, ok := f.glyphs[r]; ok {
+		return g, true
+	}
+	switch r {
+	case '‘', '’':
+		r = '\''
+	case '“', '”':
+		r = '"'
+	case '–', '—':
+		r = '-'
+	case '…':
+		r = '.'
+	case '\u00a0':
+		r = ' '
+	}
+	g, ok := f.glyphs[r]
+	return g, ok
+}
+
+// missing returns the distinct characters of text the font can't draw.
+func (f *bitmapFont) missing(text string) []rune {
+	var runes []rune
+	for _, r := range text {
+		if _, ok := f.lookup(r); !ok && !containsRune(runes, r) {
+			runes = append(runes, r)
+		}
+	}
+	return runes
+}
+
+func containsRune(runes []rune, r rune) bool {
+	for _, existing := range runes {
+		if existing == r {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *bitmapFont) lineHeight(scale int) int {
+	return (f.ascent + f.descent) * scale
+}
+
+// measure returns the width of text in pixels at the given scale.
+func (f *bitmapFont) measure(text string, scale int) int {
+	width := 0
+	for _, r := range text {
+		width += f.glyph(r).advance
+	}
+	return width * scale
+}
+
+// draw renders text with its top-left corner at (x, y), each font pixel
+// becoming a scale×scale square. It returns the x after the last glyph.
+func (f *bitmapFont) draw(dst draw.Image, text string, x, y, scale int, c color.Color) int {
+	src := image.NewUniform(c)
+	baseline := y + f.ascent*scale
+
+	for _, r := range text {
+		g := f.glyph(r)
+		top := baseline - (g.height+g.yOffset)*scale
+		for row, bits := range g.rows {
+			for col := 0; col < g.width; col++ {
+				if bits&(1<<(31-col)) == 0 {
+					continue
+				}
+				px := x + (g.xOffset+col)*scale
+				py := top + row*scale
+				draw.Draw(dst, image.Rect(px, py, px+scale, py+scale), src, image.Point{}, draw.Over)
+			}
+		}
+		x += g.advance * scale
+	}
+	return x
+}
+
+// wrap breaks text into lines no wider than maxWidth, splitting words only
+// when a single word is wider than a line. With maxLines > 0 the last kept line is
+// shortened to end in an ellipsis.
+func (f *bitmapFont) wrap(text string, scale, maxWidth, maxLines int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if maxWidth <= 0 || f.measure(candidate, scale) <= maxWidth {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			// A word longer than a whole line is split from the current one
+			if f.measure(word, scale) > maxWidth {
+				prefix := line + " "
+				if cut := f.fit(word, scale, maxWidth-f.measure(prefix, scale)); f.measure(prefix+word[:cut], scale) <= maxWidth {
+					line, word = prefix+word[:cut], word[cut:]
+				}
+			}
+			lines = append(lines, line)
+		}
+		for f.measure(word, scale) > maxWidth {
+			cut := f.fit(word, scale, maxWidth)
+			lines = append(lines, word[:cut])
+			word = word[cut:]
+		}
+		line = word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	if maxLines > 0 && len(lines) > maxLines {
+		last := []rune(lines[maxLines-1])
+		for len(last) > 0 && maxWidth > 0 && f.measure(string(last)+"...", scale) > maxWidth {
+			last = last[:len(last)-1]
+		}
+		lines = append(lines[:maxLines-1], strings.TrimRight(string(last), " ")+"...")
+	}
+	return lines
+}
+
+// fit returns how many bytes of word fit in maxWidth, at least one rune.
+func (f *bitmapFont) fit(word string, scale, maxWidth int) int {
+	width := 0
+	for i, r := range word {
+		width += f.glyph(r).advance * scale
+		if width > maxWidth {
+			if i == 0 {
+				return len(string(r))
+			}
+			return i
+		}
+	}
+	return len(word)
+}
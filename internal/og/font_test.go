@@ -0,0 +1,110 @@
+package og
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// bdf builds a font with the given glyphs after a standard header.
+func bdf(glyphs ...string) string {
+	return "STARTFONT 2.1\nSTARTPROPERTIES 2\nFONT_ASCENT 7\nFONT_DESCENT 1\nENDPROPERTIES\n" +
+		strings.Join(glyphs, "") + "ENDFONT\n"
+}
+
+const questionGlyph = "STARTCHAR question\nENCODING 63\nDWIDTH 6 0\nBBX 5 2 0 0\nBITMAP\n70\n88\nENDCHAR\n"
+
+func TestParseBDF(t *testing.T) {
+	font, err := parseBDF(bdf(questionGlyph,
+		"STARTCHAR bar\nENCODING 124\nDWIDTH 4 0\nBBX 1 3 1 -1\nBITMAP\n80\n80\n80\nENDCHAR\n",
+		"STARTCHAR unencoded\nENCODING -1\nDWIDTH 6 0\nBBX 5 1 0 0\nBITMAP\nF8\nENDCHAR\n"))
+	if err != nil {
+		t.Fatalf("parseBDF: %v", err)
+	}
+
+	if font.ascent != 7 || font.descent != 1 {
+		t.Errorf("ascent, descent = %d, %d, want 7, 1", font.ascent, font.descent)
+	}
+	if len(font.glyphs) != 2 {
+		t.Errorf("parsed %d glyphs, want 2 without the unencoded one", len(font.glyphs))
+	}
+	want := glyph{advance: 4, width: 1, height: 3, xOffset: 1, yOffset: -1, rows: []uint32{1 << 31, 1 << 31, 1 << 31}}
+	if got := font.glyphs['|']; !reflect.DeepEqual(got, want) {
+		t.Errorf("glyph '|' = %+v, want %+v", got, want)
+	}
+	if got := font.glyphs['?'].rows; !reflect.DeepEqual(got, []uint32{0x70 << 24, 0x88 << 24}) {
+		t.Errorf("glyph '?' rows = %#x", got)
+	}
+}
+
+func TestParseBDFErrors(t *testing.T) {
+	tests := map[string]string{
+		"no glyphs":         bdf(),
+		"no fallback glyph": bdf("STARTCHAR A\nENCODING 65\nDWIDTH 6 0\nBBX 5 1 0 0\nBITMAP\nF8\nENDCHAR\n"),
+		"short BBX":         bdf("STARTCHAR A\nENCODING 65\nBBX 5 1\nENDCHAR\n", questionGlyph),
+		"invalid bitmap":    bdf("STARTCHAR A\nENCODING 65\nBBX 5 1 0 0\nBITMAP\nzz\nENDCHAR\n", questionGlyph),
+		"invalid encoding":  bdf("STARTCHAR A\nENCODING A\nENDCHAR\n", questionGlyph),
+	}
+	for name, source := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseBDF(source); err == nil {
+				t.Error("parseBDF returned no error")
+			}
+		})
+	}
+}
+
+func TestEmbeddedFont(t *testing.T) {
+	for r := rune(32); r < 127; r++ {
+		if _, ok := pixelFont.glyphs[r]; !ok {
+			t.Errorf("embedded font lacks %q", r)
+		}
+	}
+	if got, want := pixelFont.glyph('’'), pixelFont.glyphs['\'']; !reflect.DeepEqual(got, want) {
+		t.Error("’ isn't drawn as '")
+	}
+	if got, want := pixelFont.glyph('é'), pixelFont.glyphs['?']; !reflect.DeepEqual(got, want) {
+		t.Error("é isn't drawn as the fallback glyph")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	// Every glyph of the embedded font is 6 pixels wide
+	tests := []struct {
+		name     string
+		text     string
+		maxWidth int
+		maxLines int
+		want     []string
+	}{
+		{name: "fits", text: "Hello world", maxWidth: 66, want: []string{"Hello world"}},
+		{name: "breaks at spaces", text: "Hello  wide world", maxWidth: 60, want: []string{"Hello wide", "world"}},
+		{name: "splits a long word", text: "ab abcdefghij", maxWidth: 36, want: []string{"ab abc", "defghi", "j"}},
+		{name: "ellipsis on the last line", text: "one two three four", maxWidth: 48, maxLines: 2, want: []string{"one two", "three..."}},
+		{name: "ellipsis shortens the line", text: "abcdefgh ijk", maxWidth: 48, maxLines: 1, want: []string{"abcde..."}},
+		{name: "no limit", text: "one two", want: []string{"one two"}},
+		{name: "empty", text: "  ", maxWidth: 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pixelFont.wrap(tt.text, 1, tt.maxWidth, tt.maxLines); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wrap = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMissing(t *testing.T) {
+	tests := map[string]string{
+		"Plain ASCII ~ 100%":      "",
+		"It’s “quoted” — really…": "",
+		"Café résumé":             "é",
+		"日本語 and 日本":              "日本語",
+	}
+	for text, want := range tests {
+		if got := string(pixelFont.missing(text)); got != want {
+			t.Errorf("missing(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
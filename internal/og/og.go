@@ -0,0 +1,375 @@
+package og
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const templatePath = "config/og.json"
+
+// Template describes a social card. Layers are drawn in field order:
+// background gradient, grid, glow, panels, logo, then texts.
+type Template struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Output string `json:"output"`
+	// Background colors are spread evenly along the top-left to bottom-right
+	// diagonal
+	Background []string `json:"background"`
+	Grid       *Grid    `json:"grid,omitempty"`
+	Glow       *Glow    `json:"glow,omitempty"`
+	Panels     []Panel  `json:"panels,omitempty"`
+	Logo       *Logo    `json:"logo,omitempty"`
+	Texts      []Text   `json:"texts"`
+	// Variables fill {name} placeholders in texts
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// Grid draws one-pixel lines every Spacing pixels.
+type Grid struct {
+	Spacing int    `json:"spacing"`
+	Color   string `json:"color"`
+}
+
+// Glow fades Color out radially from (X, Y) to transparent at Radius.
+type Glow struct {
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Radius int    `json:"radius"`
+	Color  string `json:"color"`
+}
+
+type Panel struct {
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Radius int    `json:"radius"`
+	Color  string `json:"color"`
+	Border string `json:"border,omitempty"`
+}
+
+// Logo is the site's favicon mark: a rounded square with three lines.
+type Logo struct {
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+	Size  int    `json:"size"`
+	Color string `json:"color"`
+}
+
+// Text is a block of wrapped text. Scale is the size of one font pixel, so
+// a line is 8×Scale pixels tall. A zero Y places the block Gap pixels below
+// the previous one.
+type Text struct {
+	Text     string `json:"text"`
+	X        int    `json:"x"`
+	Y        int    `json:"y"`
+	Gap      int    `json:"gap,omitempty"`
+	Scale    int    `json:"scale"`
+	Color    string `json:"color"`
+	MaxWidth int    `json:"maxWidth,omitempty"`
+	MaxLines int    `json:"maxLines,omitempty"`
+}
+
+func LoadTemplate(path string) (Template, error) {
+	var tpl Template
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tpl, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &tpl); err != nil {
+		return tpl, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if tpl.Width <= 0 || tpl.Height <= 0 {
+		return tpl, fmt.Errorf("%s: width and height must be positive", path)
+	}
+	if len(tpl.Background) == 0 {
+		return tpl, fmt.Errorf("%s: background needs at least one color", path)
+	}
+	return tpl, nil
+}
+
+// Generate renders the site's main card from config/og.json.
+func Generate() error {
+	fmt.Println("Generating OG image...")
+
+	tpl, err := LoadTemplate(templatePath)
+	if err != nil {
+		return err
+	}
+	img, err := Render(tpl, tpl.Variables)
+	if err != nil {
+		return err
+	}
+	if err := writePNG(tpl.Output, img); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Generated %s (%dx%d)\n", tpl.Output, tpl.Width, tpl.Height)
+	return nil
+}
+
+// Render draws the template with its placeholders replaced by vars. Texts
+// with characters beyond the font's ASCII set are an error rather than being
+// drawn as '?'.
+func Render(tpl Template, vars map[string]string) (*image.RGBA, error) {
+	img := image.NewRGBA(image.Rect(0, 0, tpl.Width, tpl.Height))
+
+	stops := make([]color.NRGBA, len(tpl.Background))
+	for i, value := range tpl.Background {
+		c, err := parseColor(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid background color: %w", err)
+		}
+		stops[i] = c
+	}
+	fillGradient(img, stops)
+
+	if tpl.Grid != nil && tpl.Grid.Spacing > 0 {
+		c, err := parseColor(tpl.Grid.Color)
+		if err != nil {
+			return nil, fmt.Errorf("invalid grid color: %w", err)
+		}
+		drawGrid(img, tpl.Grid.Spacing, c)
+	}
+
+	if tpl.Glow != nil && tpl.Glow.Radius > 0 {
+		c, err := parseColor(tpl.Glow.Color)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glow color: %w", err)
+		}
+		drawGlow(img, tpl.Glow.X, tpl.Glow.Y, tpl.Glow.Radius, c)
+	}
+
+	for _, panel := range tpl.Panels {
+		rect := image.Rect(panel.X, panel.Y, panel.X+panel.Width, panel.Y+panel.Height)
+		if panel.Border != "" {
+			border, err := parseColor(panel.Border)
+			if err != nil {
+				return nil, fmt.Errorf("invalid panel border: %w", err)
+			}
+			fillRoundedRect(img, rect, panel.Radius, border)
+			rect = rect.Inset(2)
+		}
+		c, err := parseColor(panel.Color)
+		if err != nil {
+			return nil, fmt.Errorf("invalid panel color: %w", err)
+		}
+		fillRoundedRect(img, rect, max(panel.Radius-2, 0), c)
+	}
+
+	if tpl.Logo != nil && tpl.Logo.Size > 0 {
+		c, err := parseColor(tpl.Logo.Color)
+		if err != nil {
+			return nil, fmt.Errorf("invalid logo color: %w", err)
+		}
+		drawLogo(img, tpl.Logo.X, tpl.Logo.Y, tpl.Logo.Size, c)
+	}
+
+	bottom := 0
+	for _, text := range tpl.Texts {
+		c, err := parseColor(text.Color)
+		if err != nil {
+			return nil, fmt.Errorf("invalid text color: %w", err)
+		}
+		scale := max(text.Scale, 1)
+		y := text.Y
+		if y == 0 {
+			y = bottom + text.Gap
+		}
+
+		expanded := expand(text.Text, vars)
+		if missing := pixelFont.missing(expanded); len(missing) > 0 {
+			return nil, fmt.Errorf("%q has characters the pixel font can't draw: %q", expanded, string(missing))
+		}
+		lines := pixelFont.wrap(expanded, scale, text.MaxWidth, text.MaxLines)
+		for _, line := range lines {
+			pixelFont.draw(img, line, text.X, y, scale, c)
+			y += pixelFont.lineHeight(scale) + 2*scale
+		}
+		if len(lines) > 0 {
+			bottom = y - 2*scale
+		}
+	}
+
+	return img, nil
+}
+
+// expand replaces {name} placeholders in text with their values in vars.
+func expand(text string, vars map[string]string) string {
+	replacements := make([]string, 0, 2*len(vars))
+	for name, value := range vars {
+		replacements = append(replacements, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(replacements...).Replace(text)
+}
+
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if err := png.Encode(file, img); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	return file.Close()
+}
+
+// parseColor accepts #rgb, #rrggbb and #rrggbbaa.
+func parseColor(value string) (color.NRGBA, error) {
+	hex := strings.TrimPrefix(value, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 || !strings.HasPrefix(value, "#") {
+		return color.NRGBA{}, fmt.Errorf("%q is not a hex color", value)
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("%q is not a hex color", value)
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// fillGradient paints a linear gradient from the top-left to the
+// bottom-right corner.
+func fillGradient(img *image.RGBA, stops []color.NRGBA) {
+	bounds := img.Bounds()
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			t := (float64(x)*w + float64(y)*h) / (w*w + h*h)
+			img.Set(x, y, gradientAt(stops, t))
+		}
+	}
+}
+
+func gradientAt(stops []color.NRGBA, t float64) color.NRGBA {
+	if len(stops) == 1 {
+		return stops[0]
+	}
+	position := math.Min(math.Max(t, 0), 1) * float64(len(stops)-1)
+	i := min(int(position), len(stops)-2)
+	return mix(stops[i], stops[i+1], position-float64(i))
+}
+
+func mix(a, b color.NRGBA, t float64) color.NRGBA {
+	lerp := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + (float64(y)-float64(x))*t))
+	}
+	return color.NRGBA{R: lerp(a.R, b.R), G: lerp(a.G, b.G), B: lerp(a.B, b.B), A: lerp(a.A, b.A)}
+}
+
+func drawGrid(img *image.RGBA, spacing int, c color.NRGBA) {
+	bounds := img.Bounds()
+	src := image.NewUniform(c)
+	for x := bounds.Min.X; x < bounds.Max.X; x += spacing {
+		draw.Draw(img, image.Rect(x, bounds.Min.Y, x+1, bounds.Max.Y), src, image.Point{}, draw.Over)
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += spacing {
+		draw.Draw(img, image.Rect(bounds.Min.X, y, bounds.Max.X, y+1), src, image.Point{}, draw.Over)
+	}
+}
+
+func drawGlow(img *image.RGBA, cx, cy, radius int, c color.NRGBA) {
+	area := image.Rect(cx-radius, cy-radius, cx+radius, cy+radius).Intersect(img.Bounds())
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			distance := math.Hypot(float64(x-cx), float64(y-cy))
+			if distance >= float64(radius) {
+				continue
+			}
+			faded := c
+			faded.A = uint8(float64(c.A) * (1 - distance/float64(radius)))
+			blend(img, x, y, faded)
+		}
+	}
+}
+
+// fillRoundedRect fills rect with rounded corners, antialiased by sampling
+// each edge pixel on a 4×4 grid.
+func fillRoundedRect(img *image.RGBA, rect image.Rectangle, radius int, c color.NRGBA) {
+	const samples = 4
+	r := float64(min(radius, rect.Dx()/2, rect.Dy()/2))
+	x0, y0 := float64(rect.Min.X), float64(rect.Min.Y)
+	x1, y1 := float64(rect.Max.X), float64(rect.Max.Y)
+
+	inside := func(x, y float64) bool {
+		// Distance to the nearest point of the rectangle shrunk by the radius
+		dx := math.Max(math.Max(x0+r-x, x-(x1-r)), 0)
+		dy := math.Max(math.Max(y0+r-y, y-(y1-r)), 0)
+		return dx*dx+dy*dy <= r*r
+	}
+
+	area := rect.Intersect(img.Bounds())
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			covered := 0
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					if inside(float64(x)+(float64(sx)+0.5)/samples, float64(y)+(float64(sy)+0.5)/samples) {
+						covered++
+					}
+				}
+			}
+			if covered == 0 {
+				continue
+			}
+			partial := c
+			partial.A = uint8(int(c.A) * covered / (samples * samples))
+			blend(img, x, y, partial)
+		}
+	}
+}
+
+// drawLogo draws the favicon at the given size: a rounded square on a 32
+// unit grid with white lines at y 10, 16 and 22.
+func drawLogo(img *image.RGBA, x, y, size int, c color.NRGBA) {
+	unit := float64(size) / 32
+	at := func(v float64) int { return int(math.Round(v * unit)) }
+
+	fillRoundedRect(img, image.Rect(x, y, x+size, y+size), at(4), c)
+
+	white := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	for _, line := range []struct{ y, end float64 }{{10, 24}, {16, 24}, {22, 18}} {
+		// Round caps extend the 2-unit stroke by a unit at both ends
+		rect := image.Rect(x+at(7), y+at(line.y-1), x+at(line.end+1), y+at(line.y+1))
+		fillRoundedRect(img, rect, at(1), white)
+	}
+}
+
+// blend composites c over the pixel at (x, y).
+func blend(img *image.RGBA, x, y int, c color.NRGBA) {
+	dst := img.RGBAAt(x, y)
+	a := uint32(c.A)
+	over := func(s, d uint8) uint8 {
+		return uint8((uint32(s)*a + uint32(d)*(255-a)) / 255)
+	}
+	img.SetRGBA(x, y, color.RGBA{
+		R: over(c.R, dst.R),
+		G: over(c.G, dst.G),
+		B: over(c.B, dst.B),
+		A: uint8(a + uint32(dst.A)*(255-a)/255),
+	})
+}
@@ -0,0 +1,23 @@
+package og
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderRejectsCharactersOutsideTheFont(t *testing.T) {
+	tpl := Template{
+		Width:      40,
+		Height:     20,
+		Background: []string{"#000"},
+		Texts:      []Text{{Text: "{title}", Scale: 1, Color: "#fff"}},
+	}
+
+	if _, err := Render(tpl, map[string]string{"title": "It’s fine…"}); err != nil {
+		t.Errorf("Render with approximated punctuation: %v", err)
+	}
+	_, err := Render(tpl, map[string]string{"title": "Café"})
+	if err == nil || !strings.Contains(err.Error(), "é") {
+		t.Errorf("Render with é = %v, want an error naming it", err)
+	}
+}
@@ -0,0 +1,320 @@
+package og
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	postTemplatePath = "config/og-post.json"
+	postsDir         = "src/content/blog"
+)
+
+// Hashes of the frontmatter and template each post card was last rendered from
+var postHashesPath = filepath.Join(".cache", "ct", "og", "posts.json")
+
+// Slugs of the posts that have a card, so the blog layout can fall back to
+// the site-wide image for the rest
+var postCardsPath = filepath.Join("src", "data", "og-posts.json")
+
+// frontmatter holds the fields of a post that appear on its card.
+type frontmatter struct {
+	Title       string
+	Description string
+	PubDate     string
+	Tags        []string
+	Draft       bool
+}
+
+// GeneratePosts renders a card per blog post from config/og-post.json into
+// public/og/<slug>.png. Posts whose frontmatter and template are unchanged
+// since the last run are skipped unless force is set. Drafts get no card, and
+// the cards of drafts and of posts rendered last run but since deleted are
+// removed so they aren't published. A post that fails to render is reported
+// and skipped without stopping the others, and its card is left out of the
+// list written to src/data/og-posts.json.
+func GeneratePosts(force bool) error {
+	fmt.Println("Generating post OG images...")
+
+	tpl, err := LoadTemplate(postTemplatePath)
+	if err != nil {
+		return err
+	}
+	templateJSON, err := json.Marshal(tpl)
+	if err != nil {
+		return fmt.Errorf("failed to encode template: %w", err)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(postsDir, "*.mdx"))
+	if err != nil {
+		return fmt.Errorf("failed to list posts: %w", err)
+	}
+	sort.Strings(paths)
+
+	previous := loadPostHashes()
+	hashes := make(map[string]string, len(paths))
+	slugs := make(map[string]bool, len(paths))
+	cards := []string{}
+	rendered, unchanged, removed, failed := 0, 0, 0, 0
+
+	for _, path := range paths {
+		slug := strings.TrimSuffix(filepath.Base(path), ".mdx")
+		slugs[slug] = true
+
+		source, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Printf("  ✗ %s: %v\n", slug, err)
+			failed++
+			continue
+		}
+		block, ok := frontmatterBlock(string(source))
+		if !ok {
+			fmt.Printf("  ⚠️  %s: no frontmatter\n", slug)
+			continue
+		}
+		post := parseFrontmatter(block)
+		if post.Draft {
+			ok, err := removeCard(expand(tpl.Output, postVariables(tpl.Variables, slug, post)))
+			if err != nil {
+				return err
+			}
+			if ok {
+				fmt.Printf("  - %s: draft, removed its card\n", slug)
+				removed++
+			}
+			continue
+		}
+
+		sum := sha256.Sum256(append(templateJSON, block...))
+		hash := hex.EncodeToString(sum[:])
+		hashes[slug] = hash
+
+		vars := postVariables(tpl.Variables, slug, post)
+		output := expand(tpl.Output, vars)
+		if !force && previous[slug] == hash && fileExists(output) {
+			cards = append(cards, slug)
+			unchanged++
+			continue
+		}
+
+		img, err := Render(tpl, vars)
+		if err == nil {
+			err = writePNG(output, img)
+		}
+		if err != nil {
+			fmt.Printf("  ✗ %s: %v\n", slug, err)
+			// A stale card would pass for this post's, and without a hash it's
+			// retried next run
+			if _, err := removeCard(output); err != nil {
+				fmt.Printf("  ⚠️  %v\n", err)
+			}
+			delete(hashes, slug)
+			failed++
+			continue
+		}
+		cards = append(cards, slug)
+		rendered++
+	}
+
+	for slug := range previous {
+		if slugs[slug] {
+			continue
+		}
+		ok, err := removeCard(expand(tpl.Output, postVariables(tpl.Variables, slug, frontmatter{})))
+		if err != nil {
+			return err
+		}
+		if ok {
+			fmt.Printf("  - %s: post deleted, removed its card\n", slug)
+			removed++
+		}
+	}
+
+	if err := savePostHashes(hashes); err != nil {
+		fmt.Printf("  ⚠️  Couldn't save post image hashes: %v\n", err)
+	}
+	if err := savePostCards(cards); err != nil {
+		fmt.Printf("  ⚠️  Couldn't save the list of post images: %v\n", err)
+	}
+
+	fmt.Printf("✓ Rendered %d post images (%d unchanged, %d removed)\n", rendered, unchanged, removed)
+	if failed > 0 {
+		return fmt.Errorf("%d post images failed to render", failed)
+	}
+	return nil
+}
+
+// postVariables adds a post's slug, title, description, date and tags to the
+// template's own variables.
+func postVariables(defaults map[string]string, slug string, post frontmatter) map[string]string {
+	vars := make(map[string]string, len(defaults)+5)
+	for name, value := range defaults {
+		vars[name] = value
+	}
+
+	date := post.PubDate
+	if t, err := time.Parse("2006-01-02", post.PubDate); err == nil {
+		date = t.Format("January 2, 2006")
+	}
+	tags := make([]string, len(post.Tags))
+	for i, tag := range post.Tags {
+		tags[i] = "#" + tag
+	}
+
+	vars["slug"] = slug
+	vars["title"] = post.Title
+	vars["description"] = post.Description
+	vars["date"] = date
+	vars["tags"] = strings.Join(tags, " ")
+	return vars
+}
+
+// frontmatterBlock returns the YAML between the leading --- fences.
+func frontmatterBlock(source string) (string, bool) {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	rest, ok := strings.CutPrefix(source, "---\n")
+	if !ok {
+		return "", false
+	}
+	block, _, ok := strings.Cut(rest, "\n---")
+	return block, ok
+}
+
+// parseFrontmatter reads the top-level keys the cards use. It covers the
+// YAML the posts are written in: quoted or plain scalars, and tag lists in
+// flow style, possibly spanning lines, or block style.
+func parseFrontmatter(block string) frontmatter {
+	var post frontmatter
+
+	values := make(map[string]string)
+	var key string
+	for _, line := range strings.Split(block, "\n") {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		// Indented lines and list items continue the previous key's value
+		if key != "" && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "- ")) {
+			values[key] += "\n" + strings.TrimSpace(line)
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			key = ""
+			continue
+		}
+		key = strings.TrimSpace(name)
+		values[key] = strings.TrimSpace(value)
+	}
+
+	post.Title = yamlScalar(values["title"])
+	post.Description = yamlScalar(values["description"])
+	post.PubDate = yamlScalar(values["pubDate"])
+	post.Tags = yamlList(values["tags"])
+	post.Draft = yamlScalar(values["draft"]) == "true"
+	return post
+}
+
+// yamlScalar unquotes a single- or double-quoted value and trims a plain one.
+func yamlScalar(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) < 2 {
+		return value
+	}
+	switch {
+	case value[0] == '\'' && value[len(value)-1] == '\'':
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	case value[0] == '"' && value[len(value)-1] == '"':
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+		return value[1 : len(value)-1]
+	}
+	if comment := strings.Index(value, " #"); comment >= 0 {
+		value = strings.TrimSpace(value[:comment])
+	}
+	return value
+}
+
+// yamlList reads a flow list like ['a', 'b'] or the "- a" lines of a block
+// list.
+func yamlList(value string) []string {
+	value = strings.TrimSpace(value)
+	var items []string
+	if inner, ok := strings.CutPrefix(value, "["); ok {
+		inner = strings.TrimSuffix(strings.TrimSpace(inner), "]")
+		items = strings.Split(inner, ",")
+	} else {
+		for _, line := range strings.Split(value, "\n") {
+			if item, ok := strings.CutPrefix(strings.TrimSpace(line), "- "); ok {
+				items = append(items, item)
+			}
+		}
+	}
+
+	var list []string
+	for _, item := range items {
+		if item = yamlScalar(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func loadPostHashes() map[string]string {
+	hashes := make(map[string]string)
+	data, err := os.ReadFile(postHashesPath)
+	if err != nil {
+		return hashes
+	}
+	if err := json.Unmarshal(data, &hashes); err != nil {
+		return make(map[string]string)
+	}
+	return hashes
+}
+
+func savePostHashes(hashes map[string]string) error {
+	if err := os.MkdirAll(filepath.Dir(postHashesPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	data, err := json.MarshalIndent(hashes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(postHashesPath, data, 0644)
+}
+
+func savePostCards(slugs []string) error {
+	if err := os.MkdirAll(filepath.Dir(postCardsPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	data, err := json.MarshalIndent(slugs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(postCardsPath, data, 0644)
+}
+
+// removeCard deletes a rendered card, reporting whether there was one.
+func removeCard(path string) (bool, error) {
+	err := os.Remove(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	return true, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
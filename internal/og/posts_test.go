@@ -0,0 +1,189 @@
+package og
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFrontmatterBlock(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+		ok     bool
+	}{
+		{name: "fenced", source: "---\ntitle: Hi\n---\nBody", want: "title: Hi", ok: true},
+		{name: "CRLF", source: "---\r\ntitle: Hi\r\n---\r\nBody", want: "title: Hi", ok: true},
+		{name: "no opening fence", source: "title: Hi\n---\n"},
+		{name: "unclosed", source: "---\ntitle: Hi\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := frontmatterBlock(tt.source)
+			if ok != tt.ok || (ok && got != tt.want) {
+				t.Errorf("frontmatterBlock = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseFrontmatter(t *testing.T) {
+	tests := []struct {
+		name  string
+		block string
+		want  frontmatter
+	}{
+		{
+			name: "quoted scalars and flow tags",
+			block: `title: 'Don''t Panic: A Guide'
+description: "Tabs\tand \"quotes\""
+pubDate: 2025-03-01
+tags: ['swift', "ios", go]`,
+			want: frontmatter{
+				Title:       "Don't Panic: A Guide",
+				Description: "Tabs\tand \"quotes\"",
+				PubDate:     "2025-03-01",
+				Tags:        []string{"swift", "ios", "go"},
+			},
+		},
+		{
+			name: "flow tags over several lines",
+			block: `title: Plain title # a comment
+tags: [
+  'rust',
+  'cli',
+]
+draft: true`,
+			want: frontmatter{Title: "Plain title", Tags: []string{"rust", "cli"}, Draft: true},
+		},
+		{
+			name: "block tags and comments",
+			block: `# leading comment
+title: Block
+tags:
+  - one
+  - 'two'
+heroImage: ./hero.png`,
+			want: frontmatter{Title: "Block", Tags: []string{"one", "two"}},
+		},
+		{
+			name:  "unindented block list",
+			block: "tags:\n- a\n- b\ndraft: false",
+			want:  frontmatter{Tags: []string{"a", "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseFrontmatter(tt.block); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFrontmatter = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostVariables(t *testing.T) {
+	vars := postVariables(map[string]string{"site": "compiledthoughts", "title": "default"}, "hello",
+		frontmatter{Title: "Hello", Description: "First post", PubDate: "2025-03-01", Tags: []string{"go", "cli"}})
+
+	want := map[string]string{
+		"site":        "compiledthoughts",
+		"slug":        "hello",
+		"title":       "Hello",
+		"description": "First post",
+		"date":        "March 1, 2025",
+		"tags":        "#go #cli",
+	}
+	if !reflect.DeepEqual(vars, want) {
+		t.Errorf("postVariables = %v, want %v", vars, want)
+	}
+}
+
+func TestGeneratePostsRemovesUnpublishedCards(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	files := map[string]string{
+		postTemplatePath: `{"width": 40, "height": 20, "output": "public/og/{slug}.png", "background": ["#000"],
+			"texts": [{"text": "{title}", "scale": 1, "color": "#fff"}]}`,
+		filepath.Join(postsDir, "published.mdx"): "---\ntitle: Published\n---\nBody",
+		filepath.Join(postsDir, "draft.mdx"):     "---\ntitle: Draft\ndraft: true\n---\nBody",
+		"public/og/draft.png":                    "stale",
+		"public/og/deleted.png":                  "stale",
+		postHashesPath:                           `{"deleted": "hash"}`,
+	}
+	for path, contents := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := GeneratePosts(false); err != nil {
+		t.Fatalf("GeneratePosts: %v", err)
+	}
+
+	if !fileExists("public/og/published.png") {
+		t.Error("published post has no card")
+	}
+	for _, path := range []string{"public/og/draft.png", "public/og/deleted.png"} {
+		if fileExists(path) {
+			t.Errorf("%s wasn't removed", path)
+		}
+	}
+}
+
+func TestGeneratePostsContinuesPastFailures(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	files := map[string]string{
+		postTemplatePath: `{"width": 40, "height": 20, "output": "public/og/{slug}.png", "background": ["#000"],
+			"texts": [{"text": "{title}", "scale": 1, "color": "#fff"}]}`,
+		filepath.Join(postsDir, "a-first.mdx"):  "---\ntitle: First\n---\nBody",
+		filepath.Join(postsDir, "b-broken.mdx"): "---\ntitle: 日本語\n---\nBody",
+		filepath.Join(postsDir, "c-last.mdx"):   "---\ntitle: Last\n---\nBody",
+		"public/og/b-broken.png":                "stale",
+	}
+	for path, contents := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := GeneratePosts(false); err == nil {
+		t.Error("GeneratePosts succeeded despite a failed post")
+	}
+
+	for _, slug := range []string{"a-first", "c-last"} {
+		if !fileExists("public/og/" + slug + ".png") {
+			t.Errorf("%s has no card", slug)
+		}
+	}
+	if fileExists("public/og/b-broken.png") {
+		t.Error("stale card of the failed post wasn't removed")
+	}
+
+	hashes := loadPostHashes()
+	if _, ok := hashes["b-broken"]; ok || len(hashes) != 2 {
+		t.Errorf("hashes = %v, want a-first and c-last only", hashes)
+	}
+
+	data, err := os.ReadFile(postCardsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cards []string
+	if err := json.Unmarshal(data, &cards); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a-first", "c-last"}; !reflect.DeepEqual(cards, want) {
+		t.Errorf("cards = %v, want %v", cards, want)
+	}
+}
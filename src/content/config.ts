@@ -11,6 +11,7 @@ const blogCollection = defineCollection({
     tags: z.array(z.string()).default([]),
     draft: z.boolean().default(false),
     series: z.string().optional(),
+    app: z.string().optional(),
   }),
 });
 
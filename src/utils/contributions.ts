@@ -0,0 +1,32 @@
+import data from '../data/contributions.json';
+
+// Mirrors ContributionCalendar in internal/github/calendar.go
+export interface ContributionDay {
+  date: string;
+  count: number;
+  level: number; // 0-4
+}
+
+export interface ContributionWeek {
+  days: ContributionDay[];
+}
+
+export interface Streak {
+  days: number;
+  start?: string;
+  end?: string;
+}
+
+export interface ContributionCalendar {
+  lastUpdated: string;
+  totalContributions: number;
+  currentStreak: number;
+  longestStreak: Streak;
+  busiestWeekday: string;
+  busiestDay?: ContributionDay;
+  weekdays: Record<string, number>;
+  weeks: ContributionWeek[];
+}
+
+// Empty until `ct fetch-github` runs with GitHub credentials
+export const contributionCalendar: ContributionCalendar = data;